@@ -0,0 +1,258 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/spf13/cobra"
+)
+
+// termPlaceholder stands for each input term in batch mode, as the
+// search term or as the value of a constraint.
+const termPlaceholder = "-"
+
+// defaultBatchWorkers is the default number of queries run at once in
+// batch mode.
+const defaultBatchWorkers = 4
+
+var (
+	// Whether to read the terms to query from the input.
+	batchFlag bool
+	// File to read batch terms from instead of stdin.
+	batchInput string
+	// Number of batch queries run at once.
+	batchWorkers int
+)
+
+// batchQueryFunc runs the batch query with the parameters for one input
+// term.
+type batchQueryFunc func(ctx context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error)
+
+// batchJob is the query for one input term and, once done is closed,
+// its outcome.
+type batchJob struct {
+	term    string
+	results []datamuseapi.APIResponse
+	err     error
+	done    chan struct{}
+}
+
+// addBatchFlags defines the flags that run a query for each term read
+// from the input.
+func addBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&batchFlag, "batch", false,
+		"Run the query for each term read from stdin, one per line, in place of '-'")
+	cmd.Flags().StringVar(&batchInput, "input", "", "Read batch terms from this file instead of stdin")
+	_ = cmd.MarkFlagFilename("input")
+	cmd.Flags().IntVar(&batchWorkers, "workers", defaultBatchWorkers, "Number of batch queries to run at once")
+}
+
+// batchRequested reports whether batch mode was requested with a flag.
+func batchRequested() bool {
+	return batchFlag || batchInput != ""
+}
+
+// termArgs requires n arguments, the last of which is a term that may be
+// omitted in batch mode.
+func termArgs(n int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if batchRequested() {
+			return cobra.RangeArgs(n-1, n)(cmd, args)
+		}
+
+		return cobra.ExactArgs(n)(cmd, args)
+	}
+}
+
+// termArg returns the argument at index i, or the term placeholder when
+// it was omitted in batch mode.
+func termArg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+
+	return termPlaceholder
+}
+
+// withTerm returns a copy of params in which the term placeholder is
+// replaced by term, and whether params contained the placeholder.
+// Related word constraints without a term of their own take the search
+// term.
+func withTerm(params datamuseapi.QueryParams, term string) (datamuseapi.QueryParams, bool) {
+	found := false
+	replace := func(value *string) {
+		if *value == termPlaceholder {
+			*value = term
+			found = true
+		}
+	}
+
+	replace(&params.Ml)
+	replace(&params.Sl)
+	replace(&params.Sp)
+
+	params.Rel = slices.Clone(params.Rel)
+	for i := range params.Rel {
+		if params.Rel[i].Term == "" {
+			params.Rel[i].Term = params.SearchTerm
+		}
+
+		replace(&params.Rel[i].Term)
+	}
+
+	return params, found
+}
+
+// batchMode reports whether the query runs in batch mode, which is the
+// case when it was requested with a flag or when '-' takes the place of
+// a term. Batch mode requires a term to replace and at least one worker.
+func batchMode(params datamuseapi.QueryParams) (bool, error) {
+	_, found := withTerm(params, "")
+
+	switch {
+	case !found && !batchRequested():
+		return false, nil
+	case !found:
+		return false, usageError(errors.New("batch mode needs '-' in place of the search term or a constraint value"))
+	case batchWorkers < 1:
+		return false, usageError(fmt.Errorf("--workers must be at least 1, got %d", batchWorkers))
+	}
+
+	return true, nil
+}
+
+// readBatchTerms reads one term per line from the --input file or stdin,
+// skipping blank lines.
+func readBatchTerms() ([]string, error) {
+	var input io.Reader = os.Stdin
+
+	if batchInput != "" && batchInput != termPlaceholder {
+		file, err := os.Open(batchInput)
+		if err != nil {
+			return nil, usageError(fmt.Errorf("failed to open input: %w", err))
+		}
+		defer file.Close()
+
+		input = file
+	}
+
+	var terms []string
+
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		if term := strings.TrimSpace(scanner.Text()); term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return terms, nil
+}
+
+// runBatch runs query for each term read from the input and prints the
+// results to stdout.
+func runBatch(ctx context.Context, params datamuseapi.QueryParams, query batchQueryFunc) error {
+	printer, err := resultprinter.NewBatchPrinter(os.Stdout, os.Stderr, displayOptions)
+	if err != nil {
+		return usageError(err)
+	}
+
+	terms, err := readBatchTerms()
+	if err != nil {
+		return err
+	}
+
+	return queryBatch(ctx, printer, os.Stderr, params, terms, batchWorkers, query)
+}
+
+// queryBatch runs query for each term with a pool of workers and prints
+// the results labeled by term in input order, each as soon as it and the
+// terms before it are done. A failed query is reported on errOut without
+// stopping the others; the first failure is returned at the end.
+func queryBatch(ctx context.Context, printer *resultprinter.BatchPrinter, errOut io.Writer,
+	params datamuseapi.QueryParams, terms []string, workers int, query batchQueryFunc,
+) error {
+	jobs := make([]*batchJob, len(terms))
+	queue := make(chan *batchJob)
+
+	for i, term := range terms {
+		jobs[i] = &batchJob{term: term, done: make(chan struct{})}
+	}
+
+	for range min(workers, len(jobs)) {
+		go func() {
+			for job := range queue {
+				termParams, _ := withTerm(params, job.term)
+				job.results, job.err = query(ctx, termParams)
+				close(job.done)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+
+		for _, job := range jobs {
+			queue <- job
+		}
+	}()
+
+	return printBatch(ctx, printer, errOut, jobs)
+}
+
+// printBatch prints the outcome of each job in order, reporting failed
+// jobs on errOut.
+func printBatch(ctx context.Context, printer *resultprinter.BatchPrinter, errOut io.Writer, jobs []*batchJob) error {
+	var (
+		firstErr error
+		failed   int
+		found    bool
+	)
+
+	for _, job := range jobs {
+		<-job.done
+
+		if ctx.Err() != nil {
+			return ctx.Err() //nolint:wrapcheck
+		}
+
+		if job.err != nil {
+			fmt.Fprintf(errOut, "Error: %s: %v\n", job.term, job.err)
+
+			if firstErr == nil {
+				firstErr = job.err
+			}
+
+			failed++
+
+			continue
+		}
+
+		if err := printer.Print(job.term, job.results); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		found = found || len(job.results) > 0
+	}
+
+	switch {
+	case firstErr != nil:
+		return fmt.Errorf("%d of %d queries failed, the first with: %w", failed, len(jobs), firstErr)
+	case !found:
+		return ErrNoResults
+	}
+
+	return nil
+}
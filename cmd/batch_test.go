@@ -0,0 +1,149 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+// batchStub is a batch query that answers each term with a result named
+// after it and records the parameters it was given. The query for the
+// first term waits until the last term has been queried, so that jobs
+// finish out of order.
+type batchStub struct {
+	mu      sync.Mutex
+	params  map[string]datamuseapi.QueryParams
+	last    string
+	lastRun chan struct{}
+	empty   map[string]bool
+	fail    map[string]error
+}
+
+func newBatchStub(terms []string) *batchStub {
+	return &batchStub{
+		params:  map[string]datamuseapi.QueryParams{},
+		last:    terms[len(terms)-1],
+		lastRun: make(chan struct{}),
+		empty:   map[string]bool{},
+		fail:    map[string]error{},
+	}
+}
+
+func (s *batchStub) query(ctx context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error) {
+	term := params.Ml
+
+	s.mu.Lock()
+	s.params[term] = params
+	s.mu.Unlock()
+
+	if term == s.last {
+		close(s.lastRun)
+	} else {
+		select {
+		case <-s.lastRun:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	switch {
+	case s.fail[term] != nil:
+		return nil, s.fail[term]
+	case s.empty[term]:
+		return nil, nil
+	}
+
+	return []datamuseapi.APIResponse{{Word: term + "-result"}}, nil
+}
+
+// runQueryBatch runs the batch query for terms with three workers and
+// returns the output, the diagnostics and the error.
+func runQueryBatch(t *testing.T, params datamuseapi.QueryParams, terms []string,
+	stub *batchStub,
+) (string, string, error) {
+	t.Helper()
+
+	var out, errOut bytes.Buffer
+
+	printer, err := resultprinter.NewBatchPrinter(&out, &errOut, resultprinter.DisplayOptions{})
+	require.NoError(t, err)
+
+	err = cmd.QueryBatch(context.Background(), printer, &errOut, params, terms, 3, stub.query)
+
+	return out.String(), errOut.String(), err
+}
+
+// TestQueryBatch_OrderedOutput tests that results are printed in input
+// order although the queries finish in another, and that the term
+// placeholder is replaced in every constraint.
+func TestQueryBatch_OrderedOutput(t *testing.T) {
+	t.Parallel()
+
+	terms := []string{"ocean", "joy", "salt"}
+	stub := newBatchStub(terms)
+	stub.empty["joy"] = true
+
+	params := datamuseapi.QueryParams{
+		Ml:         "-",
+		SearchTerm: "-",
+		Rel: []datamuseapi.RelatedWord{
+			{Code: datamuseapi.RelSynonym},
+			{Code: datamuseapi.RelTrigger, Term: "-"},
+			{Code: datamuseapi.RelRhyme, Term: "sea"},
+		},
+	}
+
+	out, errOut, err := runQueryBatch(t, params, terms, stub)
+
+	require.NoError(t, err)
+	require.Empty(t, errOut)
+	require.Equal(t, "==> ocean <==\nocean-result\n\n"+
+		"==> joy <==\n(no results)\n\n"+
+		"==> salt <==\nsalt-result\n\n", out)
+
+	require.Equal(t, []datamuseapi.RelatedWord{
+		{Code: datamuseapi.RelSynonym, Term: "joy"},
+		{Code: datamuseapi.RelTrigger, Term: "joy"},
+		{Code: datamuseapi.RelRhyme, Term: "sea"},
+	}, stub.params["joy"].Rel)
+	require.Equal(t, "-", params.Rel[1].Term, "the caller's parameters are unchanged")
+}
+
+// TestQueryBatch_Failures tests that a failed query is reported without
+// stopping the others and that the first failure is returned.
+func TestQueryBatch_Failures(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+	terms := []string{"ocean", "joy", "salt"}
+	stub := newBatchStub(terms)
+	stub.fail["joy"] = errBoom
+
+	out, errOut, err := runQueryBatch(t, datamuseapi.QueryParams{Ml: "-"}, terms, stub)
+
+	require.ErrorIs(t, err, errBoom)
+	require.EqualError(t, err, "1 of 3 queries failed, the first with: boom")
+	require.Equal(t, "Error: joy: boom\n", errOut)
+	require.Equal(t, "==> ocean <==\nocean-result\n\n==> salt <==\nsalt-result\n\n", out)
+}
+
+// TestQueryBatch_NoResults tests that a batch without results for any
+// term counts as no results.
+func TestQueryBatch_NoResults(t *testing.T) {
+	t.Parallel()
+
+	terms := []string{"xyzzy", "plugh"}
+	stub := newBatchStub(terms)
+	stub.empty["xyzzy"], stub.empty["plugh"] = true, true
+
+	_, _, err := runQueryBatch(t, datamuseapi.QueryParams{Ml: "-"}, terms, stub)
+
+	require.ErrorIs(t, err, cmd.ErrNoResults)
+}
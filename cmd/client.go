@@ -0,0 +1,217 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/quota"
+	"github.com/pierow2k/polyhymnia/internal/respcache"
+	"github.com/spf13/cobra"
+)
+
+// ClientOptions groups the flags that configure the Datamuse client.
+type ClientOptions struct {
+	BaseURL   string
+	UserAgent string
+	Headers   []string
+	Timeout   time.Duration
+	Retries   int
+	RetryWait time.Duration
+	MaxWait   time.Duration
+	NoCache   bool
+	Refresh   bool
+	CacheDir  string
+	CacheTTL  time.Duration
+	CacheMB   int64
+	RateLimit float64
+	Daily     int
+	QuotaMode string
+	QuotaWarn float64
+}
+
+// Quota modes accepted by --quota-mode.
+const (
+	quotaModeWarn   = "warn"
+	quotaModeRefuse = "refuse"
+	quotaModeOff    = "off"
+)
+
+// defaultRateLimit is the default number of requests per second.
+const defaultRateLimit = 10
+
+// clientOptions holds the client configuration shared by all commands.
+var clientOptions ClientOptions
+
+// init adds the client flags to RootCmd so that every subcommand
+// inherits them.
+func init() {
+	addClientOptionsFlags(RootCmd)
+}
+
+// addClientOptionsFlags defines the persistent flags that configure how
+// Polyhymnia talks to the Datamuse API.
+func addClientOptionsFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&clientOptions.BaseURL, "api-url", datamuseapi.DefaultBaseURL,
+		"Base URL of the Datamuse API (e.g. a caching proxy)")
+	cmd.PersistentFlags().StringVar(&clientOptions.UserAgent, "user-agent", datamuseapi.DefaultUserAgent,
+		"User-Agent header sent with API requests")
+	cmd.PersistentFlags().StringArrayVar(&clientOptions.Headers, "header", []string{},
+		"Extra request header in 'Name: value' form (repeatable)")
+	cmd.PersistentFlags().DurationVar(&clientOptions.Timeout, "timeout", datamuseapi.DefaultTimeout,
+		"Maximum duration of each API request (0 disables the limit)")
+	cmd.PersistentFlags().IntVar(&clientOptions.Retries, "retries", datamuseapi.DefaultMaxAttempts-1,
+		"Number of times to retry a request after a transient failure (0 disables retries)")
+	cmd.PersistentFlags().DurationVar(&clientOptions.RetryWait, "retry-backoff", datamuseapi.DefaultRetryDelay,
+		"Delay before the first retry, doubled for each further retry")
+	cmd.PersistentFlags().DurationVar(&clientOptions.MaxWait, "retry-max-backoff", datamuseapi.DefaultMaxDelay,
+		"Maximum delay between retries")
+	cmd.PersistentFlags().BoolVar(&clientOptions.NoCache, "no-cache", false,
+		"Do not read or write the response cache")
+	cmd.PersistentFlags().BoolVar(&clientOptions.Refresh, "refresh", false,
+		"Ignore cached responses and refresh the cache from the API")
+	cmd.PersistentFlags().StringVar(&clientOptions.CacheDir, "cache-dir", "",
+		"Directory of the response cache (default $XDG_CACHE_HOME/polyhymnia/responses)")
+	cmd.PersistentFlags().DurationVar(&clientOptions.CacheTTL, "cache-ttl", respcache.DefaultTTL,
+		"How long cached responses stay fresh (0 keeps them until evicted)")
+	cmd.PersistentFlags().Int64Var(&clientOptions.CacheMB, "cache-max-size", respcache.DefaultMaxBytes>>20,
+		"Maximum size of the response cache in MiB (0 disables the limit)")
+	cmd.PersistentFlags().Float64Var(&clientOptions.RateLimit, "rate-limit", defaultRateLimit,
+		"Maximum API requests per second (0 disables the limit)")
+	cmd.PersistentFlags().IntVar(&clientOptions.Daily, "daily-limit", quota.DefaultDailyLimit,
+		"Daily API request limit tracked across invocations")
+	cmd.PersistentFlags().StringVar(&clientOptions.QuotaMode, "quota-mode", quotaModeWarn,
+		"Action near the daily limit: warn, refuse or off")
+	cmd.PersistentFlags().Float64Var(&clientOptions.QuotaWarn, "quota-warn", quota.DefaultWarnRatio*100, //nolint:mnd
+		"Percentage of the daily limit at which to warn (0 disables the warning)")
+}
+
+// quotaWarnRatio returns the --quota-warn percentage as a fraction of
+// the daily limit.
+func quotaWarnRatio() (float64, error) {
+	if clientOptions.QuotaWarn < 0 || clientOptions.QuotaWarn > 100 {
+		return 0, usageError(fmt.Errorf("--quota-warn must be between 0 and 100, got %g", clientOptions.QuotaWarn))
+	}
+
+	return clientOptions.QuotaWarn / 100, nil //nolint:mnd
+}
+
+// newClient builds a Datamuse client from the client flags.
+func newClient() (*datamuseapi.Client, error) {
+	opts := []datamuseapi.Option{
+		datamuseapi.WithBaseURL(clientOptions.BaseURL),
+		datamuseapi.WithUserAgent(clientOptions.UserAgent),
+		datamuseapi.WithTimeout(clientOptions.Timeout),
+		datamuseapi.WithRetry(datamuseapi.RetryPolicy{
+			MaxAttempts: clientOptions.Retries + 1,
+			BaseDelay:   clientOptions.RetryWait,
+			MaxDelay:    clientOptions.MaxWait,
+		}),
+	}
+
+	if clientOptions.RateLimit > 0 {
+		burst := int(math.Ceil(clientOptions.RateLimit))
+		opts = append(opts, datamuseapi.WithRateLimit(clientOptions.RateLimit, burst))
+	}
+
+	tracker, err := newQuotaTracker()
+	if err != nil {
+		return nil, err
+	}
+
+	if tracker != nil {
+		opts = append(opts, datamuseapi.WithQuota(tracker))
+	}
+
+	if cache := newCache(); cache != nil {
+		opts = append(opts, datamuseapi.WithCache(cache))
+	}
+
+	for _, header := range clientOptions.Headers {
+		name, value, found := strings.Cut(header, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, usageError(fmt.Errorf("invalid header %q: expected 'Name: value'", header))
+		}
+
+		opts = append(opts, datamuseapi.WithHeader(strings.TrimSpace(name), strings.TrimSpace(value)))
+	}
+
+	return datamuseapi.NewClient(opts...), nil
+}
+
+// newCache opens the response cache configured by the cache flags. It
+// returns nil when caching is disabled or the cache cannot be opened, in
+// which case queries go straight to the API.
+func newCache() *respcache.DiskCache {
+	if clientOptions.NoCache {
+		return nil
+	}
+
+	dir := clientOptions.CacheDir
+	if dir == "" {
+		defaultDir, err := respcache.DefaultDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "warning: response cache disabled: %v\n", err)
+
+			return nil
+		}
+
+		dir = defaultDir
+	}
+
+	cache, err := respcache.New(respcache.Options{
+		Dir:      dir,
+		TTL:      clientOptions.CacheTTL,
+		MaxBytes: clientOptions.CacheMB << 20,
+		Refresh:  clientOptions.Refresh,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: response cache disabled: %v\n", err)
+
+		return nil
+	}
+
+	return cache
+}
+
+// newQuotaTracker returns the daily request tracker configured by the
+// quota flags, or nil when tracking is off.
+func newQuotaTracker() (*quota.Tracker, error) {
+	switch clientOptions.QuotaMode {
+	case quotaModeOff:
+		return nil, nil //nolint:nilnil
+	case quotaModeWarn, quotaModeRefuse:
+	default:
+		return nil, usageError(fmt.Errorf("invalid quota mode %q: expected warn, refuse or off", clientOptions.QuotaMode))
+	}
+
+	warnRatio, err := quotaWarnRatio()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := quota.DefaultPath()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	opts := quota.Options{
+		Path:      path,
+		Limit:     clientOptions.Daily,
+		WarnRatio: warnRatio,
+		Refuse:    clientOptions.QuotaMode == quotaModeRefuse,
+	}
+
+	if warnRatio > 0 {
+		opts.OnWarn = func(usage quota.Usage) {
+			fmt.Fprintf(os.Stderr, "warning: %d of %d daily Datamuse requests used (%.1f%%)\n",
+				usage.Count, usage.Limit, usage.Percent())
+		}
+	}
+
+	return quota.New(opts), nil
+}
@@ -0,0 +1,172 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/pierow2k/polyhymnia/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	// Configuration file given on the command line.
+	configFile string
+	// Profile selected on the command line.
+	profileName string
+)
+
+// init adds the configuration flags to RootCmd.
+func init() {
+	RootCmd.PersistentFlags().StringVar(&configFile, "config", "",
+		"Configuration file (default $XDG_CONFIG_HOME/polyhymnia/config.yaml)")
+	_ = RootCmd.MarkPersistentFlagFilename("config", "yaml", "yml")
+	RootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Configuration profile to apply")
+	_ = RootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+}
+
+// selectors are the settings that select the configuration file and
+// profile. They are read before the configuration file, so setting them
+// within it has no effect.
+//
+//nolint:gochecknoglobals
+var selectors = map[string]string{
+	"config":  "select the file with --config or " + config.EnvConfig,
+	"profile": `use the top-level "profile" key`,
+}
+
+// applyConfigBeforeArgs wraps the positional argument validator of root
+// and each of its subcommands with configuredArgs.
+func applyConfigBeforeArgs(root *cobra.Command) {
+	commands := []*cobra.Command{root}
+
+	for len(commands) > 0 {
+		command := commands[0]
+		commands = append(commands[1:], command.Commands()...)
+		command.Args = configuredArgs(command.Args)
+	}
+}
+
+// configuredArgs wraps a positional argument validator so that the
+// configuration is applied first, since cobra validates arguments before
+// running any hook and settings such as batch change which arguments are
+// required. A nil validator accepts any arguments, as cobra does for
+// subcommands.
+func configuredArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := applyConfig(cmd); err != nil {
+			return err
+		}
+
+		if validate == nil {
+			return nil
+		}
+
+		return validate(cmd, args)
+	}
+}
+
+// loadConfig loads the configuration file given by --config or
+// POLYHYMNIA_CONFIG, or the default one if it exists.
+func loadConfig() (*config.Config, error) {
+	path := configFile
+	if path == "" {
+		path = os.Getenv(config.EnvConfig)
+	}
+
+	if path != "" {
+		return config.Load(path, true) //nolint:wrapcheck
+	}
+
+	path, err := config.DefaultPath()
+	if err != nil {
+		return &config.Config{}, nil //nolint:nilerr // Without a configuration directory there is no file.
+	}
+
+	return config.Load(path, false) //nolint:wrapcheck
+}
+
+// applyConfig sets the flags of cmd that were not given on the command
+// line from POLYHYMNIA_* environment variables, the profile selected by
+// --profile, POLYHYMNIA_PROFILE or the configuration file, and the
+// configuration file's defaults, in that order of precedence.
+func applyConfig(cmd *cobra.Command) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return usageError(err)
+	}
+
+	name := profileName
+	if name == "" {
+		name = os.Getenv(config.EnvProfile)
+	}
+
+	values, err := cfg.Resolve(name)
+	if err != nil {
+		return usageError(err)
+	}
+
+	env := config.FromEnv(os.Environ())
+	warnUnknownSettings(values, env)
+
+	if err := config.Apply(cmd.Flags(), config.Merge(values, env)); err != nil {
+		return usageError(fmt.Errorf("%w (from the configuration file or environment)", err))
+	}
+
+	return nil
+}
+
+// warnUnknownSettings warns about settings from the configuration file
+// and environment variables that no command has a flag for, which are
+// otherwise ignored, and removes the selectors from values.
+func warnUnknownSettings(values, env config.Values) {
+	for _, key := range slices.Sorted(maps.Keys(selectors)) {
+		if _, ok := values[key]; ok {
+			fmt.Fprintf(os.Stderr, "warning: ignoring setting %q in the configuration file; %s\n", key, selectors[key])
+			delete(values, key)
+		}
+	}
+
+	names := settingNames()
+	known := func(key string) bool { return names[key] }
+
+	for _, key := range values.Unknown(known) {
+		fmt.Fprintf(os.Stderr, "warning: ignoring unknown setting %q in the configuration file\n", key)
+	}
+
+	for _, key := range env.Unknown(known) {
+		fmt.Fprintf(os.Stderr, "warning: ignoring unknown environment variable %s\n", config.EnvName(key))
+	}
+}
+
+// settingNames returns the names of the long flags defined by any
+// command, which are the settings the configuration may give.
+func settingNames() map[string]bool {
+	names := map[string]bool{}
+	commands := []*cobra.Command{RootCmd}
+
+	for len(commands) > 0 {
+		command := commands[0]
+		commands = append(commands[1:], command.Commands()...)
+
+		for _, flags := range []*pflag.FlagSet{command.LocalFlags(), command.PersistentFlags()} {
+			flags.VisitAll(func(flag *pflag.Flag) { names[flag.Name] = true })
+		}
+	}
+
+	return names
+}
+
+// completeProfiles offers the profiles of the configuration file for
+// shell completion of --profile.
+func completeProfiles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cfg.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
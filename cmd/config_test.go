@@ -0,0 +1,57 @@
+package cmd_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/pierow2k/polyhymnia/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSettingNames tests that the settings include the flags of every
+// command, local and persistent, and nothing else.
+func TestSettingNames(t *testing.T) {
+	t.Parallel()
+
+	names := cmd.SettingNames()
+
+	for _, name := range []string{"vocabulary", "max", "timeout", "mode", "workers", "profile"} {
+		require.True(t, names[name], name)
+	}
+
+	require.False(t, names["vocabuary"])
+}
+
+// TestConfiguredArgs tests that the configuration is applied before the
+// arguments are validated, so that a batch setting in the configuration
+// file lets the term be omitted.
+//
+//nolint:paralleltest // The configuration file is chosen by t.Setenv.
+func TestConfiguredArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("defaults:\n  batch: true\n"), 0o600))
+	t.Setenv(config.EnvConfig, path)
+
+	command := &cobra.Command{
+		Use: "define",
+		Args: cmd.ConfiguredArgs(func(command *cobra.Command, args []string) error {
+			if batch, _ := command.Flags().GetBool("batch"); batch {
+				return nil
+			}
+
+			return cobra.ExactArgs(1)(command, args)
+		}),
+		RunE: func(*cobra.Command, []string) error { return nil },
+	}
+	command.Flags().Bool("batch", false, "")
+	command.SetArgs([]string{})
+
+	require.NoError(t, command.Execute())
+
+	batch, err := command.Flags().GetBool("batch")
+	require.NoError(t, err)
+	require.True(t, batch)
+}
@@ -2,39 +2,74 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"os"
 	"strings"
 
 	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
 	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
 	"github.com/spf13/cobra"
 )
 
 var (
 	// Query parameters for the Datamuse API.
 	queryParams datamuseapi.QueryParams
+	// Related word constraints as given on the command line ("code" or
+	// "code=term").
+	relatedWords []string
 	// DisplayOptions struct to group all the display flags.
 	displayOptions resultprinter.DisplayOptions
+	// Format template given inline or as a file name.
+	formatTemplate, formatFile string
+	// Sort keys as given on the command line, e.g. "-freq".
+	sortKeys []string
 )
 
 // init adds query and display option flags to RootCmd.
 func init() {
 	addQueryParamsFlags(RootCmd)
 	addDisplayOptionsFlags(RootCmd)
+	addSortFlag(RootCmd)
+	addBatchFlags(RootCmd)
 }
 
 // addQueryParamsFlags defines the query-related flags for API
 // requests.
 func addQueryParamsFlags(cmd *cobra.Command) {
-	// Required flags
-	cmd.Flags().BoolVarP(&queryParams.Ml, "means-like", "l", false, "Words with meaning similar to this string")
-	cmd.Flags().BoolVarP(&queryParams.Sl, "sounds-like", "n", false, "Words that sound like this string")
-	cmd.Flags().BoolVarP(&queryParams.Sp, "spelled-like", "t", false, "Words spelled like this string")
-	cmd.Flags().StringArrayVar(&queryParams.RelCode, "related-word", []string{}, "Related word constraints")
-	cmd.MarkFlagsOneRequired("means-like", "related-word", "sounds-like", "spelled-like")
-	cmd.MarkFlagsMutuallyExclusive("means-like", "related-word", "sounds-like", "spelled-like")
-	// Optional flags
+	// Constraint flags (at least one is required; they may be combined)
+	addConstraintFlags(cmd, "")
+	addQueryOptionsFlags(cmd)
+}
+
+// addConstraintFlags defines the constraint flags except the one named
+// by except, which a subcommand takes from its arguments instead.
+func addConstraintFlags(cmd *cobra.Command, except string) {
+	if except != "means-like" {
+		cmd.Flags().StringVarP(&queryParams.Ml, "means-like", "l", "", "Words with meaning similar to this string")
+	}
+
+	if except != "sounds-like" {
+		cmd.Flags().StringVarP(&queryParams.Sl, "sounds-like", "n", "", "Words that sound like this string")
+	}
+
+	if except != "spelled-like" {
+		cmd.Flags().StringVarP(&queryParams.Sp, "spelled-like", "t", "", "Words spelled like this string")
+	}
+
+	if except != "related-word" {
+		cmd.Flags().StringArrayVar(&relatedWords, "related-word", []string{},
+			"Related word constraint as code or code=term (code alone uses the search term)")
+		_ = cmd.RegisterFlagCompletionFunc("related-word", completeRelationCodes)
+	}
+}
+
+// addQueryOptionsFlags defines the optional query flags that refine any
+// word query.
+func addQueryOptionsFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&queryParams.V, "vocabulary", "", "Vocabulary identifier")
 	cmd.Flags().StringArrayVar(&queryParams.Topics, "topics", []string{}, "Topics (comma-separated)")
 	cmd.Flags().StringVar(&queryParams.Lc, "left-context", "", "Left context")
@@ -54,6 +89,142 @@ func addDisplayOptionsFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&displayOptions.ShowScore, "score", "s", false, "Include score in results")
 	cmd.Flags().BoolVarP(&displayOptions.ShowQueryURL, "show-query", "q", false, "Show the URL used for the query")
 	cmd.Flags().BoolVarP(&displayOptions.ShowSyllables, "syl", "y", false, "Include syllables in results")
+	addPronunciationFlags(cmd)
+	addOutputFormatFlag(cmd)
+}
+
+// addPronunciationFlags defines the flags that choose how pronunciations
+// are written.
+func addPronunciationFlags(cmd *cobra.Command) {
+	cmd.Flags().Var(&displayOptions.PronNotation, "pron-format",
+		"Pronunciation notation ("+phonetics.NotationNames()+")")
+	_ = cmd.RegisterFlagCompletionFunc("pron-format", completePronFormats)
+	cmd.Flags().BoolVar(&queryParams.IPA, "ipa", false,
+		"Show pronunciations in IPA as given by Datamuse, unless --pron-format is set")
+}
+
+// applyIPAFlag shows pronunciations when --ipa asks Datamuse for IPA,
+// in IPA unless another notation was chosen.
+func applyIPAFlag() {
+	if !queryParams.IPA {
+		return
+	}
+
+	// Datamuse only returns IPA along with the other pronunciations.
+	displayOptions.ShowPronunciation = true
+
+	if displayOptions.PronNotation == "" {
+		displayOptions.PronNotation = phonetics.NotationIPA
+	}
+}
+
+// completePronFormats offers the pronunciation notations for shell
+// completion.
+func completePronFormats(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return strings.Split(phonetics.NotationNames(), "|"), cobra.ShellCompDirectiveNoFileComp
+}
+
+// addOutputFormatFlag defines the flag that selects the output format.
+func addOutputFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().VarP(&displayOptions.Format, "output", "o", "Output format ("+resultprinter.FormatNames()+")")
+	cmd.Flags().StringVar(&displayOptions.ListSeparator, "list-separator", resultprinter.DefaultListSeparator,
+		"Separator joining tags and definitions in CSV and TSV output")
+	cmd.Flags().StringVar(&formatTemplate, "format", "",
+		"Go template rendering each result, e.g. '{{.Word}} {{syllables .NumSyllables}}'")
+	cmd.Flags().StringVar(&formatFile, "format-file", "", "File containing a Go template rendering each result")
+	_ = cmd.MarkFlagFilename("format-file")
+}
+
+// loadFormatTemplate parses the --format or --format-file template into
+// the display options. The template flags are mutually exclusive with
+// each other and with --output.
+func loadFormatTemplate(cmd *cobra.Command) error {
+	text := formatTemplate
+
+	switch {
+	case formatTemplate != "" && formatFile != "":
+		return usageError(errors.New("--format and --format-file cannot be used together"))
+	case formatTemplate == "" && formatFile == "":
+		return nil
+	case cmd.Flags().Changed("output"):
+		return usageError(errors.New("--output cannot be used with a format template"))
+	case formatFile != "":
+		data, err := os.ReadFile(formatFile)
+		if err != nil {
+			return usageError(fmt.Errorf("failed to read format file: %w", err))
+		}
+
+		text = string(data)
+	}
+
+	tmpl, err := resultprinter.ParseTemplate(text)
+	if err != nil {
+		return usageError(err)
+	}
+
+	displayOptions.Template = tmpl
+
+	return nil
+}
+
+// addSortFlag defines the flag that sorts results on the client.
+func addSortFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&sortKeys, "sort", []string{},
+		"Sort results by "+resultsort.FieldNames()+" (prefix - for descending; later keys break ties)")
+	_ = cmd.RegisterFlagCompletionFunc("sort", completeSortKeys)
+}
+
+// completeSortKeys offers the sort keys for shell completion.
+func completeSortKeys(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	keys := make([]string, 0, 2*len(resultsort.Fields())) //nolint:mnd
+	for _, field := range resultsort.Fields() {
+		keys = append(keys, string(field), "-"+string(field))
+	}
+
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
+
+// parseSortKeys validates the --sort keys.
+func parseSortKeys() ([]resultsort.Key, error) {
+	keys, err := resultsort.Parse(sortKeys)
+	if err != nil {
+		return nil, usageError(err)
+	}
+
+	return keys, nil
+}
+
+// withMetadata adds the metadata letters that md lacks, so that data
+// needed on the client, such as frequencies for sorting, is requested.
+func withMetadata(md, letters string) string {
+	if strings.EqualFold(md, "none") {
+		md = ""
+	}
+
+	for _, letter := range letters {
+		if !strings.ContainsRune(md, letter) {
+			md += string(letter)
+		}
+	}
+
+	return md
+}
+
+// printResults displays results with the display options. When there
+// are no results the printer says so on stderr (except in
+// machine-readable formats and templates, which print an empty result
+// set) and printResults returns ErrNoResults.
+func printResults(results []datamuseapi.APIResponse) error {
+	// Display results using the resultprinter package.
+	if err := resultprinter.PrintResults(results, displayOptions); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(results) < 1 {
+		return ErrNoResults
+	}
+
+	return nil
 }
 
 // setDisplayOptionsFromMetadata parses the metadata string and
@@ -75,38 +246,141 @@ func setDisplayOptionsFromMetadata(metadata string, opts *resultprinter.DisplayO
 	}
 }
 
-// runDatamuseQuery processes command-line flags, performs the API query,
-// and displays the results.
+// constraintFlags are the names of the flags that constrain a word
+// query.
+//
+//nolint:gochecknoglobals
+var constraintFlags = []string{"means-like", "sounds-like", "spelled-like", "related-word"}
+
+// searchTermArgs accepts at most one search term, for related word
+// constraints without a term of their own. Without a constraint flag
+// the argument is taken to be a mistyped command, and is reported as
+// an unknown command with suggestions. A search term that is also a
+// command name must follow "--".
+func searchTermArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	constrained := args[0] == termPlaceholder
+	for _, name := range constraintFlags {
+		constrained = constrained || cmd.Flags().Changed(name)
+	}
+
+	if constrained {
+		if len(args) > 1 {
+			return usageError(fmt.Errorf("expected at most one search term, got %d", len(args)))
+		}
+
+		return nil
+	}
+
+	// As in cobra's own check, suggestions default to a distance of two.
+	if cmd.SuggestionsMinimumDistance <= 0 {
+		cmd.SuggestionsMinimumDistance = 2 //nolint:mnd
+	}
+
+	message := fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath())
+	if suggestions := cmd.SuggestionsFor(args[0]); len(suggestions) > 0 {
+		message += "\n\nDid you mean this?\n\t" + strings.Join(suggestions, "\n\t")
+	}
+
+	return usageError(errors.New(message))
+}
+
+// runDatamuseQuery takes the optional search term used by related word
+// constraints without a term of their own and runs the query described
+// by the command-line flags.
 func runDatamuseQuery(cmd *cobra.Command, args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("no search term provided")
+	// Assign the first argument (if any) as SearchTerm for related word
+	// constraints without a term of their own. In batch mode, the input
+	// terms take its place.
+	if len(args) == 1 {
+		queryParams.SearchTerm = args[0]
+	} else if batchRequested() {
+		queryParams.SearchTerm = termPlaceholder
 	}
 
-	// Assign the first argument as SearchTerm.
-	queryParams.SearchTerm = args[0]
+	return runWordQuery(cmd)
+}
+
+// runWordQuery validates the query parameters and flags, performs the
+// API query, and displays the filtered and sorted results. It is shared
+// by the flag-style root command and the query subcommands.
+func runWordQuery(cmd *cobra.Command) error {
+	queryParams.Rel = nil
+
+	for _, value := range relatedWords {
+		rel, err := datamuseapi.ParseRelatedWord(value)
+		if err != nil {
+			return usageError(err)
+		}
+
+		queryParams.Rel = append(queryParams.Rel, rel)
+	}
+
+	if err := queryParams.Validate(); err != nil {
+		return usageError(err)
+	}
+
+	batch, err := batchMode(queryParams)
+	if err != nil {
+		return err
+	}
+
+	if err := loadFormatTemplate(cmd); err != nil {
+		return err
+	}
+
+	keys, err := parseSortKeys()
+	if err != nil {
+		return err
+	}
+
+	if err := parseExpressions(); err != nil {
+		return err
+	}
 
 	// Set displayOptions based on metadata flag (if provided).
 	setDisplayOptionsFromMetadata(queryParams.Md, &displayOptions)
 
-	// Construct the Metadata `Md` string from the display options.
-	queryParams.Md = displayOptions.ToMetadataString(queryParams.Md)
+	applyIPAFlag()
+
+	// Construct the Metadata `Md` string from the display options and
+	// the metadata needed to filter and sort the results.
+	queryParams.Md = displayOptions.ToMetadataString(
+		withMetadata(queryParams.Md, filterMetadata()+resultsort.Metadata(keys)))
+	limit := prepareFilter(&queryParams, keys)
 
 	// Query the Datamuse API.
-	client := &http.Client{
-		Timeout: datamuseapi.RequestTimeout,
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	if batch {
+		return runBatch(cmd.Context(), queryParams,
+			func(ctx context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error) {
+				return queryWords(ctx, client, params, keys, limit)
+			})
 	}
 
-	results, err := datamuseapi.QueryAPI(queryParams, client)
+	results, err := queryWords(cmd.Context(), client, queryParams, keys, limit)
 	if err != nil {
-		return fmt.Errorf("error querying Datamuse API: %v", err)
+		return err
 	}
 
-	if len(results) < 1 {
-		fmt.Println("The search returned no results.")
-	} else {
-		// Display results using the resultprinter package.
-		resultprinter.PrintResults(results, displayOptions)
+	return printResults(results)
+}
+
+// queryWords performs a word query and filters and sorts its results.
+func queryWords(ctx context.Context, client *datamuseapi.Client, params datamuseapi.QueryParams,
+	keys []resultsort.Key, limit int,
+) ([]datamuseapi.APIResponse, error) {
+	results, err := client.QueryContext(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("error querying Datamuse API: %w", err)
 	}
 
-	return nil
+	return applyFilter(results, keys, limit)
 }
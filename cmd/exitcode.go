@@ -0,0 +1,90 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/spf13/cobra"
+)
+
+// Exit status codes returned by Polyhymnia.
+const (
+	ExitOK          = 0   // The query succeeded.
+	ExitError       = 1   // An unclassified error occurred.
+	ExitUsage       = 2   // The command line was invalid.
+	ExitNetwork     = 3   // The API could not be reached or timed out.
+	ExitAPI         = 4   // The API returned an error or an invalid response.
+	ExitNoResults   = 5   // The query succeeded but returned no results.
+	ExitQuota       = 6   // The daily request limit was reached in refuse mode.
+	ExitInterrupted = 130 // The command was interrupted with Ctrl-C.
+)
+
+// ErrNoResults reports a query that succeeded without returning any
+// words. The "no results" message has already been printed when it is
+// returned.
+var ErrNoResults = errors.New("the search returned no results")
+
+// UsageError reports an invalid command line, such as an unknown flag or
+// a missing search term.
+type UsageError struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// usageError wraps err in a UsageError unless it is nil.
+func usageError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &UsageError{Err: err}
+}
+
+// usageArgs wraps a positional argument validator so that its errors
+// are reported as usage errors.
+func usageArgs(validate cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		return usageError(validate(cmd, args))
+	}
+}
+
+// ExitCode returns the process exit status for an error returned by
+// Execute.
+func ExitCode(err error) int {
+	var (
+		usageErr   *UsageError
+		requestErr *datamuseapi.RequestError
+		statusErr  *datamuseapi.HTTPStatusError
+		decodeErr  *datamuseapi.DecodeError
+	)
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, context.Canceled):
+		return ExitInterrupted
+	case errors.As(err, &usageErr):
+		return ExitUsage
+	case errors.Is(err, ErrNoResults):
+		return ExitNoResults
+	case errors.Is(err, datamuseapi.ErrQuotaExceeded):
+		return ExitQuota
+	case errors.As(err, &requestErr):
+		return ExitNetwork
+	case errors.As(err, &statusErr), errors.As(err, &decodeErr):
+		return ExitAPI
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,62 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// TestExitCode tests that errors map to the documented exit statuses.
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "No error", err: nil, expected: cmd.ExitOK},
+		{name: "Usage error", err: &cmd.UsageError{Err: errors.New("bad flag")}, expected: cmd.ExitUsage},
+		{name: "No results", err: cmd.ErrNoResults, expected: cmd.ExitNoResults},
+		{
+			name:     "Network error",
+			err:      fmt.Errorf("query: %w", &datamuseapi.RequestError{Err: errors.New("refused")}),
+			expected: cmd.ExitNetwork,
+		},
+		{
+			name:     "HTTP status error",
+			err:      fmt.Errorf("query: %w", &datamuseapi.HTTPStatusError{StatusCode: 500}),
+			expected: cmd.ExitAPI,
+		},
+		{
+			name:     "Decode error",
+			err:      fmt.Errorf("query: %w", &datamuseapi.DecodeError{Err: errors.New("bad json")}),
+			expected: cmd.ExitAPI,
+		},
+		{
+			name:     "Quota exceeded",
+			err:      fmt.Errorf("query: %w", datamuseapi.ErrQuotaExceeded),
+			expected: cmd.ExitQuota,
+		},
+		{
+			name:     "Interrupted",
+			err:      &datamuseapi.RequestError{Err: context.Canceled},
+			expected: cmd.ExitInterrupted,
+		},
+		{name: "Other error", err: errors.New("disk full"), expected: cmd.ExitError},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := cmd.ExitCode(testCase.err); got != testCase.expected {
+				t.Errorf("ExitCode() = %d, want %d", got, testCase.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package cmd
+
+// SearchTermArgs exposes searchTermArgs to the tests.
+var SearchTermArgs = searchTermArgs //nolint:gochecknoglobals
+
+// QueryBatch exposes queryBatch to the tests.
+var QueryBatch = queryBatch //nolint:gochecknoglobals
+
+// FilterResults exposes filterResults to the tests.
+var FilterResults = filterResults //nolint:gochecknoglobals
+
+// ConfiguredArgs exposes configuredArgs to the tests.
+var ConfiguredArgs = configuredArgs //nolint:gochecknoglobals
+
+// RunQuota exposes runQuota to the tests.
+var RunQuota = runQuota //nolint:gochecknoglobals
+
+// RunTerminal exposes runTerminal to the tests.
+var RunTerminal = runTerminal //nolint:gochecknoglobals
+
+// SettingNames exposes settingNames to the tests.
+var SettingNames = settingNames //nolint:gochecknoglobals
@@ -0,0 +1,155 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
+	"github.com/pierow2k/polyhymnia/internal/resultexpr"
+	"github.com/pierow2k/polyhymnia/internal/resultfilter"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// resultFilter holds the client-side filter flags.
+	resultFilter resultfilter.Filter
+	// Filter and ranking expressions as given on the command line.
+	whereExpr, rankByExpr string
+	// Parsed filter and ranking expressions, or nil when not given.
+	whereCondition, rankScore *resultexpr.Expr
+)
+
+// init adds the filter flags to RootCmd.
+func init() {
+	addFilterFlags(RootCmd)
+}
+
+// addFilterFlags defines the flags that filter results on the client.
+func addFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&resultFilter.POS, "only-pos", []string{},
+		"Keep words with any of these parts of speech (n, v, adj, adv, u)")
+	cmd.Flags().Var(&resultFilter.Syllables, "syllables", "Keep words with this many syllables, e.g. 2 or 2-3")
+	cmd.Flags().Float64Var(&resultFilter.MinFreq, "min-freq", 0,
+		"Keep words at least this frequent (occurrences per million words)")
+	cmd.Flags().Var(&resultFilter.Length, "length", "Keep words with this many characters, e.g. 4-7")
+	cmd.Flags().BoolVar(&resultFilter.SingleWord, "single-word", false, "Drop multiword expressions")
+	addStressFlag(cmd)
+	_ = cmd.RegisterFlagCompletionFunc("only-pos", completePartsOfSpeech)
+	cmd.Flags().StringVar(&whereExpr, "where", "",
+		`Keep words matching an expression, e.g. 'syllables == 2 && pos has "adj"'`)
+	cmd.Flags().StringVar(&rankByExpr, "rank-by", "", "Order words by a numeric expression, highest first")
+}
+
+// parseExpressions parses the --where and --rank-by expressions.
+func parseExpressions() error {
+	whereCondition, rankScore = nil, nil
+
+	if whereExpr != "" {
+		condition, err := resultexpr.ParseCondition(whereExpr)
+		if err != nil {
+			return usageError(fmt.Errorf("invalid --where expression: %w", err))
+		}
+
+		whereCondition = condition
+	}
+
+	if rankByExpr != "" {
+		score, err := resultexpr.ParseScore(rankByExpr)
+		if err != nil {
+			return usageError(fmt.Errorf("invalid --rank-by expression: %w", err))
+		}
+
+		rankScore = score
+	}
+
+	return nil
+}
+
+// filterMetadata returns the metadata letters needed by the filter flags
+// and expressions.
+func filterMetadata() string {
+	letters := resultFilter.Metadata()
+
+	for _, expr := range []*resultexpr.Expr{whereCondition, rankScore} {
+		if expr != nil {
+			letters += expr.Metadata()
+		}
+	}
+
+	return letters
+}
+
+// addStressFlag defines the flag that filters results by stress pattern.
+func addStressFlag(cmd *cobra.Command) {
+	cmd.Flags().Var(&resultFilter.Stress, "stress",
+		"Keep words with this stress pattern, e.g. 01 or iamb (0 unstressed, 1 stressed, ? any)")
+	_ = cmd.RegisterFlagCompletionFunc("stress", completeMeters)
+}
+
+// completeMeters offers the names of metrical feet for shell completion.
+func completeMeters(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return phonetics.MeterNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completePartsOfSpeech offers the Datamuse part-of-speech codes for
+// shell completion.
+func completePartsOfSpeech(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return []string{"n\tnoun", "v\tverb", "adj\tadjective", "adv\tadverb", "u\tundetermined"},
+		cobra.ShellCompDirectiveNoFileComp
+}
+
+// prepareFilter raises the result limit of params to the API maximum
+// when a filter is active, so that enough results remain after
+// filtering, or when ranking or sorting by keys, so that the
+// best-ranked results are kept, and returns the limit the user asked
+// for.
+func prepareFilter(params *datamuseapi.QueryParams, keys []resultsort.Key) int {
+	limit := params.Max
+
+	if resultFilter.Active() || whereCondition != nil || rankScore != nil || len(keys) > 0 {
+		params.Max = datamuseapi.MaxResults
+	}
+
+	return limit
+}
+
+// applyFilter returns the results that pass the filter flags and the
+// --where expression, ordered by the --rank-by expression and the sort
+// keys and truncated to limit (if positive).
+func applyFilter(results []datamuseapi.APIResponse, keys []resultsort.Key, limit int,
+) ([]datamuseapi.APIResponse, error) {
+	return filterResults(results, &resultFilter, whereCondition, rankScore, keys, limit)
+}
+
+// filterResults returns the results that pass filter and the where
+// condition (if not nil), ordered by the rank score (if not nil) and
+// then by keys, and truncated to limit (if positive). Ordering comes
+// first, so that the best-ranked results are kept.
+func filterResults(results []datamuseapi.APIResponse, filter *resultfilter.Filter, where, rank *resultexpr.Expr,
+	keys []resultsort.Key, limit int,
+) ([]datamuseapi.APIResponse, error) {
+	results = filter.Apply(results)
+
+	if where != nil {
+		var err error
+		if results, err = resultexpr.Filter(results, where); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+
+	if rank != nil {
+		if err := resultexpr.Rank(results, rank); err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+
+	resultsort.Sort(results, keys)
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultexpr"
+	"github.com/pierow2k/polyhymnia/internal/resultfilter"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFilterResults_RankBeforeLimit tests that the best-ranked results
+// are kept rather than the first ones returned by the API.
+func TestFilterResults_RankBeforeLimit(t *testing.T) {
+	t.Parallel()
+
+	results := []datamuseapi.APIResponse{
+		{Word: "sea", Frequency: 1},
+		{Word: "deep", Frequency: 5},
+		{Word: "main", Frequency: 3},
+		{Word: "ocean", Frequency: 9},
+		{Word: "brine", Frequency: 7},
+	}
+
+	where, err := resultexpr.ParseCondition(`word != "ocean"`)
+	require.NoError(t, err)
+
+	rank, err := resultexpr.ParseScore("freq")
+	require.NoError(t, err)
+
+	kept, err := cmd.FilterResults(results, &resultfilter.Filter{}, where, rank, nil, 2)
+	require.NoError(t, err)
+
+	words := make([]string, len(kept))
+	for i, result := range kept {
+		words[i] = result.Word
+	}
+
+	require.Equal(t, []string{"brine", "deep"}, words)
+}
+
+// TestFilterResults_SortBeforeLimit tests that the first results by the
+// sort keys are kept rather than the first ones returned by the API.
+func TestFilterResults_SortBeforeLimit(t *testing.T) {
+	t.Parallel()
+
+	results := []datamuseapi.APIResponse{
+		{Word: "sea"},
+		{Word: "deep"},
+		{Word: "main"},
+		{Word: "brine"},
+	}
+
+	keys, err := resultsort.Parse([]string{"alpha"})
+	require.NoError(t, err)
+
+	kept, err := cmd.FilterResults(results, &resultfilter.Filter{}, nil, nil, keys, 2)
+	require.NoError(t, err)
+
+	words := make([]string, len(kept))
+	for i, result := range kept {
+		words[i] = result.Word
+	}
+
+	require.Equal(t, []string{"brine", "deep"}, words)
+}
@@ -0,0 +1,184 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// MeansCmd finds words with a meaning similar to a term.
+	MeansCmd = &cobra.Command{
+		Use:   "means <term>",
+		Short: "Find words with a meaning similar to a term",
+		Long: "Means finds words with a meaning similar to a word or phrase (ml),\n" +
+			"like 'polyhymnia --means-like <term>'.",
+		Example: "  polyhymnia means ocean --spelled-like 's*' --syllables 1",
+		Args:    usageArgs(termArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queryParams.Ml = termArg(args, 0)
+
+			return runWordQuery(cmd)
+		},
+		ValidArgsFunction: completeNoFiles,
+	}
+	// SoundsCmd finds words that sound like a term.
+	SoundsCmd = &cobra.Command{
+		Use:   "sounds <term>",
+		Short: "Find words that sound like a term",
+		Long: "Sounds finds words that sound like a word, even a misspelled one (sl),\n" +
+			"like 'polyhymnia --sounds-like <term>'.",
+		Example: "  polyhymnia sounds jirraf",
+		Args:    usageArgs(termArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queryParams.Sl = termArg(args, 0)
+
+			return runWordQuery(cmd)
+		},
+		ValidArgsFunction: completeNoFiles,
+	}
+	// SpelledCmd finds words that match a spelling pattern.
+	SpelledCmd = &cobra.Command{
+		Use:   "spelled <pattern>",
+		Short: "Find words spelled like a pattern",
+		Long: "Spelled finds words that match a spelling pattern (sp), in which * matches\n" +
+			"any number of letters and ? a single letter, like\n" +
+			"'polyhymnia --spelled-like <pattern>'.",
+		Example: "  polyhymnia spelled 't??k' --def",
+		Args:    usageArgs(termArgs(1)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queryParams.Sp = termArg(args, 0)
+
+			return runWordQuery(cmd)
+		},
+		ValidArgsFunction: completeNoFiles,
+	}
+	// RelatedCmd finds words related to a term by a relation code.
+	RelatedCmd = &cobra.Command{
+		Use:   "related <code> <term>",
+		Short: "Find words related to a term, such as synonyms or antonyms",
+		Long: "Related finds words that stand in the relation given by a code to a term\n" +
+			"(rel_<code>), like 'polyhymnia --related-word <code> <term>'. Run\n" +
+			"'polyhymnia relations' to list the codes.",
+		Example: "  polyhymnia related syn joy\n  polyhymnia related jja ocean --means-like calm",
+		Args:    usageArgs(termArgs(2)), //nolint:mnd
+		RunE: func(cmd *cobra.Command, args []string) error {
+			relatedWords = []string{args[0]}
+			queryParams.SearchTerm = termArg(args, 1)
+
+			return runWordQuery(cmd)
+		},
+		ValidArgsFunction: completeRelatedArgs,
+	}
+	// DefineCmd prints the definitions of a word.
+	DefineCmd = &cobra.Command{
+		Use:   "define <word>",
+		Short: "Print the definitions of a word",
+		Long: "Define looks up a word (sp=<word>&md=d&max=1) and prints its definitions,\n" +
+			"each preceded by its part of speech.",
+		Example:           "  polyhymnia define polyhymnia --pro --pron-format ipa",
+		Args:              usageArgs(cobra.ExactArgs(1)),
+		RunE:              runDefine,
+		ValidArgsFunction: completeNoFiles,
+	}
+)
+
+// init adds the shared word query flags to each query subcommand and
+// registers them with RootCmd.
+func init() {
+	modes := []struct {
+		cmd        *cobra.Command
+		constraint string
+	}{
+		{MeansCmd, "means-like"},
+		{SoundsCmd, "sounds-like"},
+		{SpelledCmd, "spelled-like"},
+		{RelatedCmd, "related-word"},
+	}
+
+	for _, mode := range modes {
+		addWordQueryFlags(mode.cmd, mode.constraint)
+		RootCmd.AddCommand(mode.cmd)
+	}
+}
+
+// addWordQueryFlags defines the options shared by the word query
+// subcommands: the constraints other than the one taken from the
+// arguments, and the query, display, sort, filter and batch flags.
+func addWordQueryFlags(cmd *cobra.Command, constraint string) {
+	addConstraintFlags(cmd, constraint)
+	addQueryOptionsFlags(cmd)
+	addDisplayOptionsFlags(cmd)
+	addSortFlag(cmd)
+	addFilterFlags(cmd)
+	addBatchFlags(cmd)
+}
+
+// completeNoFiles disables file name completion for arguments that are
+// words.
+func completeNoFiles(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRelatedArgs offers the relation codes for the first argument
+// of the related command.
+func completeRelatedArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeRelationCodes(cmd, args, toComplete)
+	}
+
+	return completeNoFiles(cmd, args, toComplete)
+}
+
+// init adds the define flags and registers DefineCmd with RootCmd.
+func init() {
+	addDefineFlags(DefineCmd)
+	RootCmd.AddCommand(DefineCmd)
+}
+
+// addDefineFlags defines the flags that add details to a definition.
+func addDefineFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&queryParams.V, "vocabulary", "", "Vocabulary identifier")
+	cmd.Flags().BoolVarP(&displayOptions.ShowFrequency, "freq", "f", false, "Include frequency")
+	cmd.Flags().BoolVarP(&displayOptions.ShowPOS, "pos", "p", false, "Include parts of speech")
+	cmd.Flags().BoolVarP(&displayOptions.ShowPronunciation, "pro", "r", false, "Include pronunciation")
+	cmd.Flags().BoolVarP(&displayOptions.ShowSyllables, "syl", "y", false, "Include syllables")
+	cmd.Flags().BoolVarP(&displayOptions.ShowQueryURL, "show-query", "q", false, "Show the URL used for the query")
+	addPronunciationFlags(cmd)
+	addOutputFormatFlag(cmd)
+}
+
+// runDefine looks up the word argument and displays its definitions. A
+// word without definitions counts as no results.
+func runDefine(cmd *cobra.Command, args []string) error {
+	if err := loadFormatTemplate(cmd); err != nil {
+		return err
+	}
+
+	displayOptions.ShowDefinitions = true
+	applyIPAFlag()
+	queryParams.Sp = termArg(args, 0)
+	queryParams.Max = 1
+	queryParams.Md = displayOptions.ToMetadataString("")
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	results, err := client.QueryContext(cmd.Context(), queryParams)
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	if len(results) > 1 {
+		results = results[:1]
+	}
+
+	if len(results) > 0 && len(results[0].Definitions) == 0 {
+		results = nil
+	}
+
+	return printResults(results)
+}
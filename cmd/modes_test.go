@@ -0,0 +1,73 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModeCommands_Flags tests that each query subcommand omits the
+// constraint flag it takes from its arguments and keeps the others.
+func TestModeCommands_Flags(t *testing.T) {
+	t.Parallel()
+
+	constraints := []string{"means-like", "sounds-like", "spelled-like", "related-word"}
+	tests := map[string]string{
+		"means":   "means-like",
+		"sounds":  "sounds-like",
+		"spelled": "spelled-like",
+		"related": "related-word",
+	}
+
+	for name, omitted := range tests {
+		command, _, err := cmd.RootCmd.Find([]string{name})
+		require.NoError(t, err)
+		require.Equal(t, name, command.Name())
+
+		for _, constraint := range constraints {
+			if constraint == omitted {
+				require.Nil(t, command.Flags().Lookup(constraint), name)
+			} else {
+				require.NotNil(t, command.Flags().Lookup(constraint), name)
+			}
+		}
+
+		require.NotNil(t, command.Flags().Lookup("sort"), name)
+		require.NotNil(t, command.Flags().Lookup("where"), name)
+	}
+}
+
+// TestRootCmd_FlagStyle tests that the flag-style invocation remains
+// available on the root command.
+func TestRootCmd_FlagStyle(t *testing.T) {
+	t.Parallel()
+
+	for _, flag := range []string{"means-like", "sounds-like", "spelled-like", "related-word", "max"} {
+		require.NotNil(t, cmd.RootCmd.Flags().Lookup(flag), flag)
+	}
+}
+
+// TestSearchTermArgs tests that an argument without a constraint flag
+// is reported as an unknown command, while a search term given with a
+// constraint flag is accepted.
+func TestSearchTermArgs(t *testing.T) {
+	t.Parallel()
+
+	root := &cobra.Command{Use: "polyhymnia"}
+	root.Flags().StringArray("related-word", []string{}, "")
+	root.AddCommand(&cobra.Command{Use: "define", Run: func(*cobra.Command, []string) {}})
+
+	require.NoError(t, cmd.SearchTermArgs(root, nil))
+	require.NoError(t, cmd.SearchTermArgs(root, []string{"-"}))
+
+	err := cmd.SearchTermArgs(root, []string{"defne"})
+	require.ErrorContains(t, err, `unknown command "defne" for "polyhymnia"`)
+	require.ErrorContains(t, err, "Did you mean this?\n\tdefine")
+	require.Equal(t, cmd.ExitUsage, cmd.ExitCode(err))
+
+	require.NoError(t, root.Flags().Set("related-word", "syn"))
+	require.NoError(t, cmd.SearchTermArgs(root, []string{"define"}))
+	require.ErrorContains(t, cmd.SearchTermArgs(root, []string{"joy", "day"}), "at most one search term")
+}
@@ -0,0 +1,55 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pierow2k/polyhymnia/internal/quota"
+	"github.com/spf13/cobra"
+)
+
+// QuotaCmd shows how many API requests have been made today.
+var QuotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Show today's Datamuse API request usage",
+	Long: "Quota shows how many Datamuse API requests Polyhymnia has made today (UTC)\n" +
+		"against the daily limit set with --daily-limit. Cached responses are not counted.",
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runQuota,
+}
+
+// init registers QuotaCmd with RootCmd.
+func init() {
+	RootCmd.AddCommand(QuotaCmd)
+}
+
+// runQuota prints today's request count, the limit and the remaining
+// requests, warning when usage is past the --quota-warn threshold.
+func runQuota(cmd *cobra.Command, _ []string) error {
+	warnRatio, err := quotaWarnRatio()
+	if err != nil {
+		return err
+	}
+
+	path, err := quota.DefaultPath()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	usage, err := quota.New(quota.Options{Path: path, Limit: clientOptions.Daily}).Usage()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintf(out, "Date (UTC): %s\n", usage.Date)
+	fmt.Fprintf(out, "Requests: %d of %d (%.1f%%)\n", usage.Count, usage.Limit, usage.Percent())
+	fmt.Fprintf(out, "Remaining: %d\n", usage.Remaining())
+
+	if warnRatio > 0 && usage.Percent() >= warnRatio*100 {
+		fmt.Fprintln(out, "Warning: approaching the daily request limit.")
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunQuota tests that the quota command reads today's counter from
+// the state directory and writes the usage to the command's output.
+//
+//nolint:paralleltest // The state directory is chosen by t.Setenv.
+func TestRunQuota(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	today := time.Now().UTC().Format("2006-01-02")
+	path := filepath.Join(stateDir, "polyhymnia", "quota.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(`{"date":%q,"count":95000}`, today)), 0o600))
+
+	var out bytes.Buffer
+
+	command := &cobra.Command{}
+	command.SetOut(&out)
+
+	require.NoError(t, cmd.RunQuota(command, nil))
+	require.Equal(t, "Date (UTC): "+today+"\n"+
+		"Requests: 95000 of 100000 (95.0%)\n"+
+		"Remaining: 5000\n"+
+		"Warning: approaching the daily request limit.\n", out.String())
+}
@@ -0,0 +1,51 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/spf13/cobra"
+)
+
+// RelationsCmd lists the relation codes accepted by --related-word.
+var RelationsCmd = &cobra.Command{
+	Use:   "relations",
+	Short: "List the relation codes accepted by --related-word",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  runRelations,
+}
+
+// init registers RelationsCmd with RootCmd.
+func init() {
+	RootCmd.AddCommand(RelationsCmd)
+}
+
+// runRelations prints every relation code with its description and an
+// example.
+func runRelations(_ *cobra.Command, _ []string) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "CODE\tDESCRIPTION\tEXAMPLE")
+
+	for _, info := range datamuseapi.Relations() {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", info.Code, info.Description, info.Example)
+	}
+
+	return writer.Flush() //nolint:wrapcheck
+}
+
+// completeRelationCodes offers the relation codes and their
+// descriptions for shell completion of --related-word.
+func completeRelationCodes(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	relations := datamuseapi.Relations()
+	completions := make([]string, 0, len(relations))
+
+	for _, info := range relations {
+		completions = append(completions, fmt.Sprintf("%s\t%s", info.Code, info.Description))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
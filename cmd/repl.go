@@ -0,0 +1,141 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/repl"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// replPrompt is shown before each line typed at a terminal.
+const replPrompt = "polyhymnia> "
+
+// ReplCmd runs queries interactively.
+//
+//nolint:gochecknoglobals
+var ReplCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Run queries interactively with shared settings",
+	Long: "Repl reads queries such as 'ml ocean' or 'rhy day syl=2' and commands such as\n" +
+		"':set max 20' one line at a time. Settings persist from one query to the next,\n" +
+		"and ':filter' and ':sort' refine the previous results. The flags set the\n" +
+		"initial settings. Type ':help' in the session for the full list.",
+	Args: usageArgs(cobra.NoArgs),
+	RunE: runRepl,
+}
+
+// init adds the initial settings flags and registers ReplCmd with
+// RootCmd.
+func init() {
+	addQueryOptionsFlags(ReplCmd)
+	addDisplayOptionsFlags(ReplCmd)
+	addSortFlag(ReplCmd)
+	RootCmd.AddCommand(ReplCmd)
+}
+
+// runRepl starts a session with the settings given by the flags. At a
+// terminal, lines are edited in raw mode (refer to runTerminal);
+// otherwise they are read as they come, so that a script of queries can
+// be piped in.
+func runRepl(cmd *cobra.Command, _ []string) error {
+	if err := loadFormatTemplate(cmd); err != nil {
+		return err
+	}
+
+	keys, err := parseSortKeys()
+	if err != nil {
+		return err
+	}
+
+	setDisplayOptionsFromMetadata(queryParams.Md, &displayOptions)
+	queryParams.Md = ""
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	settings := repl.Settings{Params: queryParams, Options: displayOptions, Sort: keys}
+	fd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		session := repl.NewSession(client, os.Stdout, os.Stderr, settings)
+
+		return session.Run(cmd.Context(), repl.NewLineReader(os.Stdin)) //nolint:wrapcheck
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer term.Restore(fd, state) //nolint:errcheck
+
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		width, height = 0, 0
+	}
+
+	return runTerminal(cmd.Context(), os.Stdin, os.Stdout, width, height, client, settings)
+}
+
+// runTerminal runs a session at a terminal in raw mode that reads keys
+// from in and writes to out, with history and completion of command
+// names. Since Ctrl-C does not interrupt in raw mode, the keys are read
+// in the background so that Ctrl-C or Esc cancels a running query and
+// returns to the prompt. A width of zero leaves the default size.
+func runTerminal(ctx context.Context, in io.Reader, out io.Writer, width, height int, client repl.Querier,
+	settings repl.Settings,
+) error {
+	keyboard := repl.NewKeyboard(in)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{keyboard, out}, replPrompt)
+	terminal.AutoCompleteCallback = completeReplCommand
+
+	if width > 0 {
+		_ = terminal.SetSize(width, height)
+	}
+
+	fmt.Fprintln(terminal, "Type :help for help and :quit or Ctrl-D to leave. Ctrl-C or Esc cancels a query.")
+
+	// In raw mode, the terminal translates line endings, so all output
+	// goes through it.
+	session := repl.NewSession(client, terminal, terminal, settings)
+	session.SetInterrupter(keyboard)
+
+	return session.Run(ctx, terminal) //nolint:wrapcheck
+}
+
+// completeReplCommand completes the command name at the start of the
+// line when Tab is pressed and exactly one name matches.
+func completeReplCommand(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || strings.Contains(line[:pos], " ") {
+		return "", 0, false
+	}
+
+	var match string
+
+	for _, name := range repl.Commands() {
+		if strings.HasPrefix(name, line[:pos]) {
+			if match != "" {
+				return "", 0, false
+			}
+
+			match = name
+		}
+	}
+
+	if match == "" {
+		return "", 0, false
+	}
+
+	return match + " " + line[pos:], len(match) + 1, true
+}
@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/cmd"
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/repl"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingQuerier answers no query until its context is canceled, and
+// reports each query it starts.
+type blockingQuerier struct {
+	started chan struct{}
+}
+
+func (q *blockingQuerier) QueryContext(ctx context.Context, _ datamuseapi.QueryParams) (
+	[]datamuseapi.APIResponse, error,
+) {
+	q.started <- struct{}{}
+	<-ctx.Done()
+
+	return nil, ctx.Err()
+}
+
+func (q *blockingQuerier) SuggestContext(ctx context.Context, _ datamuseapi.SuggestParams) (
+	[]datamuseapi.APIResponse, error,
+) {
+	return q.QueryContext(ctx, datamuseapi.QueryParams{})
+}
+
+// lockedBuffer is a bytes.Buffer that is safe for concurrent use.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p) //nolint:wrapcheck
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// TestRunTerminal_CancelQuery tests that Ctrl-C and Esc cancel a running
+// query at the terminal and return to the prompt rather than ending the
+// session.
+func TestRunTerminal_CancelQuery(t *testing.T) {
+	t.Parallel()
+
+	in, keys := io.Pipe()
+	out := &lockedBuffer{}
+	querier := &blockingQuerier{started: make(chan struct{})}
+	settings := repl.Settings{Params: datamuseapi.QueryParams{Max: 10}}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- cmd.RunTerminal(context.Background(), in, out, 80, 24, querier, settings)
+	}()
+
+	canceled := func(n int) func() bool {
+		return func() bool { return strings.Count(out.String(), "Query canceled.") == n }
+	}
+
+	for i, key := range []string{"\x03", "\x1b"} {
+		_, err := keys.Write([]byte("ml ocean\r"))
+		require.NoError(t, err)
+
+		select {
+		case <-querier.started:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "the query did not start")
+		}
+
+		_, err = keys.Write([]byte(key))
+		require.NoError(t, err)
+		require.Eventually(t, canceled(i+1), 5*time.Second, 10*time.Millisecond)
+	}
+
+	_, err := keys.Write([]byte(":quit\r"))
+	require.NoError(t, err)
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the session did not end")
+	}
+}
@@ -0,0 +1,138 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Query parameters shared by the perfect and near rhyme queries.
+	rhymeParams datamuseapi.QueryParams
+	// RhymeCmd finds perfect and near rhymes of a word.
+	RhymeCmd = &cobra.Command{
+		Use:   "rhyme <word>",
+		Short: "Find perfect and near rhymes of a word",
+		Long: "Rhyme looks up perfect rhymes (rel_rhy) and near rhymes (rel_nry) of a\n" +
+			"word and lists them like a rhyming dictionary, grouped by syllable\n" +
+			"count with perfect rhymes first.",
+		Args: usageArgs(cobra.ExactArgs(1)),
+		RunE: runRhymeQuery,
+	}
+)
+
+// init adds the rhyme flags and registers RhymeCmd with RootCmd.
+func init() {
+	addRhymeParamsFlags(RhymeCmd)
+	RootCmd.AddCommand(RhymeCmd)
+}
+
+// addRhymeParamsFlags defines the flags that constrain rhymes and the
+// output flags that apply to them.
+func addRhymeParamsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&rhymeParams.Ml, "means-like", "l", "", "Only rhymes with a meaning similar to this string")
+	cmd.Flags().StringArrayVar(&rhymeParams.Topics, "topics", []string{}, "Topics (comma-separated)")
+	cmd.Flags().StringVar(&rhymeParams.V, "vocabulary", "", "Vocabulary identifier")
+	cmd.Flags().IntVar(&rhymeParams.Max, "max", 100, "Maximum number of rhymes of each kind to return (1-1000)")
+	addStressFlag(cmd)
+	cmd.Flags().BoolVarP(&displayOptions.ShowCountFlag, "count", "c", false, "Show number of words returned by query")
+	cmd.Flags().BoolVarP(&displayOptions.ShowQueryURL, "show-query", "q", false, "Show the URL used for the query")
+	addOutputFormatFlag(cmd)
+}
+
+// runRhymeQuery queries the perfect and near rhymes of the word argument
+// concurrently and displays them.
+func runRhymeQuery(cmd *cobra.Command, args []string) error {
+	if err := loadFormatTemplate(cmd); err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	// Syllable counts are needed to group the rhymes.
+	rhymeParams.Md = displayOptions.ToMetadataString(withMetadata(rhymeParams.Md, "s"+filterMetadata()))
+	limit := prepareFilter(&rhymeParams, nil)
+
+	perfect, near, err := queryRhymes(cmd.Context(), client, args[0], rhymeParams)
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	if perfect, err = applyFilter(perfect, nil, limit); err != nil {
+		return err
+	}
+
+	if near, err = applyFilter(near, nil, limit); err != nil {
+		return err
+	}
+
+	printer := resultprinter.NewPrinter(os.Stdout, os.Stderr, displayOptions)
+	if err := printer.PrintRhymes(args[0], perfect, near); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(perfect) == 0 && len(near) == 0 {
+		return ErrNoResults
+	}
+
+	return nil
+}
+
+// queryRhymes runs the perfect and near rhyme queries for word
+// concurrently. Near rhymes that are also perfect rhymes are dropped.
+func queryRhymes(ctx context.Context, client *datamuseapi.Client, word string,
+	params datamuseapi.QueryParams,
+) ([]datamuseapi.APIResponse, []datamuseapi.APIResponse, error) {
+	codes := []datamuseapi.RelationCode{datamuseapi.RelRhyme, datamuseapi.RelNearRhyme}
+	results := make([][]datamuseapi.APIResponse, len(codes))
+	errs := make([]error, len(codes))
+
+	var wg sync.WaitGroup
+
+	for i, code := range codes {
+		query := params
+		query.Rel = []datamuseapi.RelatedWord{{Code: code, Term: word}}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i], errs[i] = client.QueryContext(ctx, query)
+		}()
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	perfect, near := results[0], results[1]
+
+	seen := make(map[string]bool, len(perfect))
+	for _, rhyme := range perfect {
+		seen[rhyme.Word] = true
+	}
+
+	distinct := near[:0]
+
+	for _, rhyme := range near {
+		if !seen[rhyme.Word] {
+			distinct = append(distinct, rhyme)
+		}
+	}
+
+	return perfect, distinct, nil
+}
@@ -2,8 +2,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
 	"github.com/spf13/cobra"
 )
 
@@ -19,14 +24,56 @@ var (
 		Short:   "Polyhymnia enables users to search for words\nbased on meaning, sound, spelling, and relationships.",
 		Long:    "Polyhymnia leverages the Datamuse API to enable users to search for words\nbased on meaning, sound, spelling, and relationships.",
 		Version: fmt.Sprintf("%s - Build Date: %s", Version, BuildDate),
+		Args:    searchTermArgs,
 		RunE:    runDatamuseQuery,
+		// Errors are reported by Execute, and the usage message is only
+		// shown for usage errors.
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 )
 
+// init reports flag parsing errors as usage errors.
+func init() {
+	RootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return usageError(err)
+	})
+}
+
 // Execute adds all child commands to the root command and sets flags.
+// The configuration is applied to each command before its arguments are
+// validated. The command runs with a context that is canceled when the process
+// receives an interrupt, so in-flight requests stop on Ctrl-C. Errors
+// are printed to stderr before being returned; pass them to ExitCode to
+// obtain the exit status.
 func Execute() error {
-	if err := RootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	applyConfigBeforeArgs(RootCmd)
+
+	if err := RootCmd.ExecuteContext(ctx); err != nil {
+		reportError(err)
+
 		return err
 	}
 	return nil
 }
+
+// reportError prints err to stderr in a form suited to its kind.
+func reportError(err error) {
+	var usageErr *UsageError
+
+	switch {
+	case errors.Is(err, ErrNoResults):
+		// The "no results" message has already been printed.
+	case errors.Is(err, context.Canceled):
+		fmt.Fprintln(os.Stderr, "Interrupted.")
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(os.Stderr, "Error: %v\nRun 'polyhymnia --help' for usage.\n", err)
+	case datamuseapi.IsTimeout(err):
+		fmt.Fprintf(os.Stderr, "Error: %v\nThe request timed out; try a longer --timeout.\n", err)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
@@ -0,0 +1,71 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Query parameters for the Datamuse autocomplete endpoint.
+	suggestParams datamuseapi.SuggestParams
+	// SuggestCmd suggests completions for a partially typed word.
+	SuggestCmd = &cobra.Command{
+		Use:   "suggest <prefix>",
+		Short: "Suggest words that complete the given prefix",
+		Long: "Suggest uses the Datamuse autocomplete endpoint to return words\n" +
+			"that complete a partially typed word or phrase, ordered by popularity.",
+		Args: usageArgs(cobra.ExactArgs(1)),
+		RunE: runSuggestQuery,
+	}
+)
+
+// init adds the suggest flags and registers SuggestCmd with RootCmd.
+func init() {
+	addSuggestParamsFlags(SuggestCmd)
+	RootCmd.AddCommand(SuggestCmd)
+}
+
+// addSuggestParamsFlags defines the flags for autocomplete requests and
+// the display flags that apply to suggestions.
+func addSuggestParamsFlags(cmd *cobra.Command) {
+	cmd.Flags().IntVar(&suggestParams.Max, "max", 10, "Maximum number of suggestions to return (1-1000)")
+	cmd.Flags().StringVar(&suggestParams.V, "vocabulary", "", "Vocabulary identifier")
+	cmd.Flags().BoolVarP(&displayOptions.ShowCountFlag, "count", "c", false, "Show number of words returned by query")
+	cmd.Flags().BoolVarP(&displayOptions.ShowScore, "score", "s", false, "Include score in results")
+	cmd.Flags().BoolVarP(&displayOptions.ShowQueryURL, "show-query", "q", false, "Show the URL used for the query")
+	addOutputFormatFlag(cmd)
+	addSortFlag(cmd)
+}
+
+// runSuggestQuery queries the autocomplete endpoint with the prefix
+// argument and displays the suggestions.
+func runSuggestQuery(cmd *cobra.Command, args []string) error {
+	suggestParams.S = args[0]
+
+	if err := loadFormatTemplate(cmd); err != nil {
+		return err
+	}
+
+	keys, err := parseSortKeys()
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	results, err := client.SuggestContext(cmd.Context(), suggestParams)
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	resultsort.Sort(results, keys)
+
+	return printResults(results)
+}
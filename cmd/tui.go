@@ -0,0 +1,82 @@
+// Package cmd handles the command-line interface for Polyhymnia.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Mode of the first query in the browser: ml, sl, sp or a relation
+	// code.
+	tuiMode string
+	// TuiCmd browses results and pivots between queries on a full-screen
+	// terminal display.
+	TuiCmd = &cobra.Command{
+		Use:   "tui <term>",
+		Short: "Browse results and pivot between queries in a full-screen view",
+		Long: "Tui shows the results of a query in a searchable list beside the details\n" +
+			"of the highlighted word. Keys turn the highlighted word into a new query\n" +
+			"for words with a similar meaning (m or Enter), synonyms (s), antonyms (a)\n" +
+			"or rhymes (r); Backspace returns to the previous query, / searches the\n" +
+			"list and q quits.",
+		Example:           "  polyhymnia tui ocean\n  polyhymnia tui --mode syn happy",
+		Args:              usageArgs(cobra.ExactArgs(1)),
+		RunE:              runTui,
+		ValidArgsFunction: completeNoFiles,
+	}
+)
+
+// init adds the tui flags and registers TuiCmd with RootCmd.
+func init() {
+	addTuiFlags(TuiCmd)
+	RootCmd.AddCommand(TuiCmd)
+}
+
+// addTuiFlags defines the flags for the browser's queries.
+func addTuiFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&tuiMode, "mode", "ml",
+		"Kind of the first query: ml, sl, sp or a relation code such as syn")
+	_ = cmd.RegisterFlagCompletionFunc("mode", completeTuiModes)
+	cmd.Flags().IntVar(&queryParams.Max, "max", 100, "Maximum number of results of each query (1-1000)")
+	cmd.Flags().StringVar(&queryParams.V, "vocabulary", "", "Vocabulary identifier")
+	cmd.Flags().StringArrayVar(&queryParams.Topics, "topics", []string{}, "Topics (comma-separated)")
+}
+
+// completeTuiModes offers the query kinds for shell completion of
+// --mode.
+func completeTuiModes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	codes, directive := completeRelationCodes(cmd, args, toComplete)
+
+	return append([]string{"ml\tMeans like", "sl\tSounds like", "sp\tSpelled like"}, codes...), directive
+}
+
+// runTui starts the browser with the query given by --mode and the term
+// argument. The details pane needs every kind of metadata, so it is
+// always requested.
+func runTui(cmd *cobra.Command, args []string) error {
+	switch tuiMode {
+	case "ml", "sl", "sp":
+	default:
+		code, err := datamuseapi.ParseRelationCode(tuiMode)
+		if err != nil {
+			return usageError(fmt.Errorf("invalid --mode: %w", err))
+		}
+
+		tuiMode = string(code)
+	}
+
+	client, err := newClient()
+	if err != nil {
+		return err
+	}
+
+	queryParams.Md = "dfprs"
+
+	return tui.Run(cmd.Context(), os.Stdin, os.Stdout, client, queryParams, //nolint:wrapcheck
+		tui.Query{Mode: tuiMode, Term: args[0]})
+}
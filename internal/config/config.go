@@ -0,0 +1,228 @@
+// Package config loads Polyhymnia's configuration file and environment
+// variables and applies them as defaults for command-line flags.
+//
+// Settings are named after the long flags they set and are layered in
+// this order of precedence, from highest to lowest: flags given on the
+// command line, POLYHYMNIA_* environment variables, the selected profile,
+// the defaults in the configuration file, and the built-in defaults.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the names of the environment variables that set
+// flags, e.g. POLYHYMNIA_MAX for --max.
+const EnvPrefix = "POLYHYMNIA_"
+
+// Environment variables that select the configuration file and the
+// profile rather than setting a flag.
+const (
+	EnvConfig  = EnvPrefix + "CONFIG"
+	EnvProfile = EnvPrefix + "PROFILE"
+)
+
+// ErrUnknownProfile reports a profile that the configuration file does
+// not define.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// ErrInvalidConfig reports a configuration file that cannot be parsed or
+// a setting with an invalid value.
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// Value is the value of a setting: a single value, or several for flags
+// that may be repeated, such as --topics.
+type Value []string
+
+// UnmarshalYAML accepts a scalar or a sequence of scalars.
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		var values []string
+		if err := node.Decode(&values); err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		*v = values
+
+		return nil
+	}
+
+	var value string
+	if err := node.Decode(&value); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	*v = Value{value}
+
+	return nil
+}
+
+// Values maps setting names, which are long flag names such as "max",
+// to values.
+type Values map[string]Value
+
+// Config is the content of the configuration file.
+type Config struct {
+	// Profile is the profile used when none is selected with --profile or
+	// POLYHYMNIA_PROFILE.
+	Profile string `yaml:"profile"`
+	// Defaults apply to every invocation.
+	Defaults Values `yaml:"defaults"`
+	// Profiles are named sets of settings that override the defaults.
+	Profiles map[string]Values `yaml:"profiles"`
+}
+
+// DefaultPath returns the default configuration file,
+// "polyhymnia/config.yaml" under the user's configuration directory
+// ($XDG_CONFIG_HOME or ~/.config on Linux).
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate configuration directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "polyhymnia", "config.yaml"), nil
+}
+
+// Load reads the configuration file at path. A missing file is an empty
+// configuration unless mustExist is set.
+func Load(path string, mustExist bool) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !mustExist {
+		return &Config{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	var config Config
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("%w %s: %w", ErrInvalidConfig, path, err)
+	}
+
+	return &config, nil
+}
+
+// Resolve returns the settings of the configuration file's defaults
+// overridden by those of the named profile, or of the file's default
+// profile when name is empty.
+func (c *Config) Resolve(name string) (Values, error) {
+	if name == "" {
+		name = c.Profile
+	}
+
+	if name == "" {
+		return Merge(c.Defaults), nil
+	}
+
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (defined profiles: %s)", ErrUnknownProfile, name, strings.Join(c.ProfileNames(), ", "))
+	}
+
+	return Merge(c.Defaults, profile), nil
+}
+
+// ProfileNames returns the names of the profiles in alphabetical order.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// FromEnv returns the settings given by POLYHYMNIA_* variables in
+// environ, as returned by os.Environ. The rest of the variable name, in
+// lower case with underscores for hyphens, names the flag; for example,
+// POLYHYMNIA_LIST_SEPARATOR sets --list-separator. POLYHYMNIA_CONFIG and
+// POLYHYMNIA_PROFILE are not settings.
+func FromEnv(environ []string) Values {
+	values := Values{}
+
+	for _, variable := range environ {
+		name, value, _ := strings.Cut(variable, "=")
+		if !strings.HasPrefix(name, EnvPrefix) || name == EnvConfig || name == EnvProfile {
+			continue
+		}
+
+		key := strings.ReplaceAll(strings.ToLower(strings.TrimPrefix(name, EnvPrefix)), "_", "-")
+		values[key] = Value{value}
+	}
+
+	return values
+}
+
+// EnvName returns the environment variable that sets key, e.g.
+// POLYHYMNIA_LIST_SEPARATOR for "list-separator".
+func EnvName(key string) string {
+	return EnvPrefix + strings.ReplaceAll(strings.ToUpper(key), "-", "_")
+}
+
+// Unknown returns the keys of the settings, in order, that known
+// rejects, such as misspelled flag names.
+func (v Values) Unknown(known func(key string) bool) []string {
+	var unknown []string
+
+	for key := range v {
+		if !known(key) {
+			unknown = append(unknown, key)
+		}
+	}
+
+	slices.Sort(unknown)
+
+	return unknown
+}
+
+// Merge combines layers of settings, later layers overriding earlier
+// ones.
+func Merge(layers ...Values) Values {
+	merged := Values{}
+
+	for _, layer := range layers {
+		for key, value := range layer {
+			merged[key] = value
+		}
+	}
+
+	return merged
+}
+
+// Apply sets each flag that was not given on the command line to its
+// value in values, leaving the flag marked as unchanged. Settings for
+// flags that flags does not define are ignored, since the same settings
+// serve every command; use Unknown to find settings that no command
+// defines.
+func Apply(flags *pflag.FlagSet, values Values) error {
+	var err error
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		value, ok := values[flag.Name]
+		if !ok || flag.Changed || err != nil {
+			return
+		}
+
+		for _, item := range value {
+			if setErr := flag.Value.Set(item); setErr != nil {
+				err = fmt.Errorf("%w: invalid value %q for %s: %w", ErrInvalidConfig, item, flag.Name, setErr)
+
+				return
+			}
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,159 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/config"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleConfig = `
+defaults:
+  max: 50
+  pos: true
+profiles:
+  spanish-poetry:
+    vocabulary: es
+    max: 30
+    syl: true
+    pro: true
+  topical:
+    topics: [sea, sailing]
+`
+
+// loadSample writes sampleConfig to a file and loads it.
+func loadSample(t *testing.T) *config.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(sampleConfig), 0o600))
+
+	cfg, err := config.Load(path, true)
+	require.NoError(t, err)
+
+	return cfg
+}
+
+// newFlagSet returns flags like those of the root command.
+func newFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Int("max", 100, "")
+	flags.String("vocabulary", "", "")
+	flags.Bool("syl", false, "")
+	flags.Bool("pro", false, "")
+	flags.Bool("pos", false, "")
+	flags.StringArray("topics", []string{}, "")
+
+	return flags
+}
+
+func TestLoad_Missing(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "missing.yaml")
+
+	cfg, err := config.Load(path, false)
+	require.NoError(t, err)
+	require.Empty(t, cfg.Profiles)
+
+	_, err = config.Load(path, true)
+	require.Error(t, err)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("profiles: [unclosed"), 0o600))
+
+	_, err := config.Load(path, true)
+	require.ErrorIs(t, err, config.ErrInvalidConfig)
+}
+
+func TestConfig_Resolve(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadSample(t)
+
+	values, err := cfg.Resolve("")
+	require.NoError(t, err)
+	require.Equal(t, config.Values{"max": {"50"}, "pos": {"true"}}, values)
+
+	values, err = cfg.Resolve("spanish-poetry")
+	require.NoError(t, err)
+	require.Equal(t, config.Value{"30"}, values["max"])
+	require.Equal(t, config.Value{"es"}, values["vocabulary"])
+	require.Equal(t, config.Value{"true"}, values["pos"])
+
+	_, err = cfg.Resolve("sonnets")
+	require.ErrorIs(t, err, config.ErrUnknownProfile)
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Parallel()
+
+	values := config.FromEnv([]string{
+		"HOME=/root",
+		"POLYHYMNIA_MAX=20",
+		"POLYHYMNIA_LIST_SEPARATOR=|",
+		"POLYHYMNIA_PROFILE=spanish-poetry",
+	})
+
+	require.Equal(t, config.Values{"max": {"20"}, "list-separator": {"|"}}, values)
+}
+
+func TestValues_Unknown(t *testing.T) {
+	t.Parallel()
+
+	values := config.Values{"max": {"20"}, "vocabuary": {"es"}, "syl": {"true"}, "colour": {"on"}}
+	known := func(key string) bool { return key == "max" || key == "syl" }
+
+	require.Equal(t, []string{"colour", "vocabuary"}, values.Unknown(known))
+	require.Empty(t, config.Values{"max": {"20"}}.Unknown(known))
+	require.Equal(t, "POLYHYMNIA_LIST_SEPARATOR", config.EnvName("list-separator"))
+}
+
+// TestApply tests the precedence of flags over the environment, the
+// environment over the profile and the profile over the defaults.
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	cfg := loadSample(t)
+	profile, err := cfg.Resolve("spanish-poetry")
+	require.NoError(t, err)
+
+	flags := newFlagSet()
+	require.NoError(t, flags.Parse([]string{"--vocabulary", "en"}))
+
+	values := config.Merge(profile, config.FromEnv([]string{"POLYHYMNIA_MAX=20"}))
+	require.NoError(t, config.Apply(flags, values))
+
+	maxResults, _ := flags.GetInt("max")
+	vocabulary, _ := flags.GetString("vocabulary")
+	syllables, _ := flags.GetBool("syl")
+	pos, _ := flags.GetBool("pos")
+
+	require.Equal(t, 20, maxResults)
+	require.Equal(t, "en", vocabulary)
+	require.True(t, syllables)
+	require.True(t, pos)
+	require.False(t, flags.Changed("max"))
+}
+
+func TestApply_Lists(t *testing.T) {
+	t.Parallel()
+
+	values, err := loadSample(t).Resolve("topical")
+	require.NoError(t, err)
+
+	flags := newFlagSet()
+	require.NoError(t, config.Apply(flags, values))
+
+	topics, _ := flags.GetStringArray("topics")
+	require.Equal(t, []string{"sea", "sailing"}, topics)
+
+	require.ErrorIs(t, config.Apply(newFlagSet(), config.Values{"max": {"many"}}), config.ErrInvalidConfig)
+}
@@ -0,0 +1,32 @@
+package datamuseapi
+
+import (
+	"net/url"
+)
+
+// Cache stores raw API response bodies keyed by the normalized query
+// URL. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached body for key and whether it was found.
+	Get(key string) ([]byte, bool)
+	// Set stores body under key.
+	Set(key string, body []byte) error
+}
+
+// WithCache sets the cache consulted before sending a request and
+// updated after every successful response.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// cacheKey normalizes a query URL for use as a cache key by sorting its
+// query parameters, so that equivalent queries share an entry.
+func cacheKey(queryURL *url.URL) string {
+	normalized := *queryURL
+	normalized.RawQuery = normalized.Query().Encode()
+	normalized.Fragment = ""
+
+	return normalized.String()
+}
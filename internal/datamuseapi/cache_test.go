@@ -0,0 +1,82 @@
+package datamuseapi_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCache is an in-memory datamuseapi.Cache for tests.
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+}
+
+func (m *memoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	body, ok := m.entries[key]
+
+	return body, ok
+}
+
+func (m *memoryCache) Set(key string, body []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = body
+
+	return nil
+}
+
+func TestClient_Cache_ServesRepeatedQueries(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "")
+	cache := &memoryCache{entries: map[string][]byte{}}
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), datamuseapi.WithCache(cache))
+
+	for range 3 {
+		results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean", Max: 1})
+
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, "sea", results[0].Word)
+	}
+
+	require.Equal(t, int32(1), requests.Load())
+	require.Contains(t, cache.entries, server.URL+"/words?max=1&ml=ocean")
+}
+
+func TestClient_Cache_IgnoresCorruptEntry(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "")
+	cache := &memoryCache{entries: map[string][]byte{
+		server.URL + "/words?ml=ocean": []byte("not json"),
+	}}
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), datamuseapi.WithCache(cache))
+
+	results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, int32(1), requests.Load())
+	require.JSONEq(t, `[{"word":"sea","score":100}]`, string(cache.entries[server.URL+"/words?ml=ocean"]))
+}
+
+func TestClient_Cache_DoesNotStoreFailures(t *testing.T) {
+	t.Parallel()
+
+	server, _ := flakyServer(t, "", 500)
+	cache := &memoryCache{entries: map[string][]byte{}}
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), datamuseapi.WithCache(cache))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.ErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.Empty(t, cache.entries)
+}
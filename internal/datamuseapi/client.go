@@ -0,0 +1,300 @@
+package datamuseapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultBaseURL is the base URL of the public Datamuse API.
+const DefaultBaseURL = "https://api.datamuse.com"
+
+// DefaultUserAgent is the User-Agent header sent with every request
+// unless overridden with WithUserAgent.
+const DefaultUserAgent = "polyhymnia"
+
+// wordsPath is the path of the Datamuse words endpoint relative to the
+// base URL.
+const wordsPath = "/words"
+
+// Client queries the Datamuse API. A Client is safe for concurrent use
+// and should be reused rather than created for every query.
+type Client struct {
+	baseURL    string
+	userAgent  string
+	headers    http.Header
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	retry      RetryPolicy
+	cache      Cache
+	limiter    *rateLimiter
+	quota      QuotaTracker
+	errorLog   io.Writer
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL sets the base URL the client sends requests to, such as a
+// caching proxy or a local test server. Endpoint paths like "/words" are
+// appended to it.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds an extra header sent with every request. It may be
+// given several times to add several headers or values.
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.headers.Add(key, value)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests. The client
+// is not modified; WithTransport applies to a copy.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
+	}
+}
+
+// WithTransport sets the round tripper used to send requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithTimeout sets the maximum duration allowed for a single request.
+// A timeout of zero disables the per-request limit, leaving only the
+// deadline of the caller's context.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithErrorLog sets the writer that receives diagnostics which do not
+// affect the result of a query, such as a failure to close a response
+// body. It defaults to os.Stderr; a nil writer discards diagnostics.
+func WithErrorLog(w io.Writer) Option {
+	return func(c *Client) {
+		if w == nil {
+			w = io.Discard
+		}
+
+		c.errorLog = w
+	}
+}
+
+// NewClient returns a Client for the Datamuse API configured with the
+// given options.
+func NewClient(opts ...Option) *Client {
+	client := &Client{
+		baseURL:    DefaultBaseURL,
+		userAgent:  DefaultUserAgent,
+		headers:    make(http.Header),
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+		errorLog:   os.Stderr,
+	}
+
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	// Apply the transport to a copy so that a caller-owned HTTP client
+	// is never modified.
+	if client.transport != nil {
+		httpClient := *client.httpClient
+		httpClient.Transport = client.transport
+		client.httpClient = &httpClient
+	}
+
+	return client
+}
+
+// BaseURL returns the base URL the client sends requests to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Query sends a request to the words endpoint based on the provided
+// query parameters and returns the parsed API response or an error.
+func (c *Client) Query(queryParams QueryParams) ([]APIResponse, error) {
+	return c.QueryContext(context.Background(), queryParams)
+}
+
+// QueryContext is like Query but sends the request with the given
+// context, so the caller can cancel it or shorten its deadline.
+func (c *Client) QueryContext(ctx context.Context, queryParams QueryParams) ([]APIResponse, error) {
+	// Build the query URL.
+	queryURL := queryParams.buildQueryURL(c.baseURL)
+
+	apiResponses, err := c.get(ctx, queryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the API response to extract pronunciation, frequency, and
+	// the query URL for each result.
+	return parseAPIResponse(apiResponses, queryURL), nil
+}
+
+// get returns the decoded response for queryURL, from the cache when a
+// fresh entry exists and otherwise by sending a GET request, retrying
+// transient failures according to the retry policy.
+func (c *Client) get(ctx context.Context, queryURL string) ([]APIResponse, error) {
+	// Parse and validate the queryURL.
+	parsedURL, err := url.Parse(queryURL)
+	if err != nil || !parsedURL.IsAbs() {
+		return nil, fmt.Errorf("%w: invalid URL: %s", ErrAPIError, queryURL)
+	}
+
+	var key string
+
+	if c.cache != nil {
+		key = cacheKey(parsedURL)
+
+		// A corrupt entry is ignored and replaced by a fresh response.
+		if body, ok := c.cache.Get(key); ok {
+			if apiResponses, err := decodeResponse(body); err == nil {
+				return apiResponses, nil
+			}
+		}
+	}
+
+	body, err := c.fetchWithRetry(ctx, queryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	apiResponses, err := decodeResponse(body)
+	if err != nil {
+		return nil, &DecodeError{URL: queryURL, Body: truncateBody(body), Err: err}
+	}
+
+	// A failure to write the cache does not affect the query result.
+	if c.cache != nil {
+		_ = c.cache.Set(key, body)
+	}
+
+	return apiResponses, nil
+}
+
+// decodeResponse unmarshals a JSON response body into a slice of
+// APIResponse.
+func decodeResponse(body []byte) ([]APIResponse, error) {
+	var apiResponses []APIResponse
+
+	err := json.Unmarshal(body, &apiResponses)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return apiResponses, nil
+}
+
+// fetch sends a single GET request for queryURL and returns the response
+// body. On failure, the returned retryHint tells whether the failure is
+// transient and how long the server asked the client to wait.
+func (c *Client) fetch(ctx context.Context, queryURL string) ([]byte, retryHint, error) {
+	// Wait for the rate limiter and check the quota before sending.
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, retryHint{}, &RequestError{URL: queryURL, Err: err}
+		}
+	}
+
+	if c.quota != nil {
+		if err := c.quota.Acquire(); errors.Is(err, ErrQuotaExceeded) {
+			return nil, retryHint{}, err
+		} else if err != nil {
+			fmt.Fprintf(c.errorLog, "warning: request not counted: %v\n", err)
+		}
+	}
+
+	// Limit the duration of the API request when a timeout is set. The
+	// caller's context is kept to tell a timeout, which is transient,
+	// from the caller giving up.
+	reqCtx := ctx
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		reqCtx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	// Build an HTTP GET request with the specified context and query URL.
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, queryURL, nil)
+	if err != nil {
+		return nil, retryHint{}, fmt.Errorf("%w: failed to create request: %w", ErrAPIError, err)
+	}
+
+	for key, values := range c.headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	// Send the request using the configured HTTP client. Transport
+	// errors are transient unless the caller's context has ended.
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		hint := retryHint{retryable: ctx.Err() == nil}
+
+		return nil, hint, &RequestError{URL: queryURL, Err: err}
+	}
+
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			fmt.Fprintf(c.errorLog, "error closing response body: %v\n", closeErr)
+		}
+	}()
+
+	// Check if the response status is '200 OK' to ensure a successful
+	// request.
+	if resp.StatusCode != http.StatusOK {
+		hint := retryHint{
+			retryable: isRetryableStatus(resp.StatusCode),
+			after:     parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+
+		// Keep the start of the body to help diagnose the failure.
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody+1))
+
+		return nil, hint, &HTTPStatusError{StatusCode: resp.StatusCode, URL: queryURL, Body: truncateBody(body)}
+	}
+
+	// Read the response body into a byte slice for further processing.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, retryHint{retryable: ctx.Err() == nil},
+			&RequestError{URL: queryURL, Err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+
+	return body, retryHint{}, nil
+}
@@ -0,0 +1,204 @@
+package datamuseapi_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClient_Query_CustomServer verifies that a Client sends requests to
+// the configured base URL with the configured headers.
+func TestClient_Query_CustomServer(t *testing.T) {
+	t.Parallel()
+
+	var gotRequest *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequest = r
+
+		_, _ = w.Write([]byte(`[{"word":"sea","score":100}]`))
+	}))
+	defer server.Close()
+
+	client := datamuseapi.NewClient(
+		datamuseapi.WithBaseURL(server.URL+"/datamuse/"),
+		datamuseapi.WithUserAgent("polyhymnia-test"),
+		datamuseapi.WithHeader("X-Team", "lexicography"),
+	)
+
+	results, err := client.Query(datamuseapi.QueryParams{
+		Ml:  "ocean",
+		Max: 1,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "sea", results[0].Word)
+	require.Equal(t, server.URL+"/datamuse/words?ml=ocean&max=1", results[0].QueryURL)
+	require.Equal(t, "/datamuse/words", gotRequest.URL.Path)
+	require.Equal(t, "polyhymnia-test", gotRequest.Header.Get("User-Agent"))
+	require.Equal(t, "lexicography", gotRequest.Header.Get("X-Team"))
+}
+
+// TestNewClient_DoesNotModifyHTTPClient verifies that transport and
+// timeout options do not change a caller-owned HTTP client.
+func TestNewClient_DoesNotModifyHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{}
+
+	client := datamuseapi.NewClient(
+		datamuseapi.WithBaseURL(server.URL),
+		datamuseapi.WithHTTPClient(httpClient),
+		datamuseapi.WithTransport(server.Client().Transport),
+		datamuseapi.WithTimeout(time.Second),
+	)
+
+	results, err := client.Query(datamuseapi.QueryParams{Sp: "pyro"})
+
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.Nil(t, httpClient.Transport)
+	require.Zero(t, httpClient.Timeout)
+}
+
+// TestClient_QueryContext_Canceled verifies that canceling the caller's
+// context aborts an in-flight request.
+func TestClient_QueryContext_Canceled(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), datamuseapi.WithTimeout(0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	results, err := client.QueryContext(ctx, datamuseapi.QueryParams{Sp: "pyro"})
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.Nil(t, results)
+}
+
+// failingQuota is a datamuseapi.QuotaTracker whose Acquire always
+// returns err.
+type failingQuota struct {
+	err error
+}
+
+func (q failingQuota) Acquire() error {
+	return q.err
+}
+
+func TestClient_Quota_RefusesRequest(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "")
+	refused := fmt.Errorf("%w: 2 of 2 requests used", datamuseapi.ErrQuotaExceeded)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL),
+		datamuseapi.WithQuota(failingQuota{err: refused}))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.ErrorIs(t, err, datamuseapi.ErrQuotaExceeded)
+	require.NotErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.Zero(t, requests.Load())
+}
+
+// TestClient_Quota_CounterFailure verifies that a failure to update the
+// request counter is only a warning.
+func TestClient_Quota_CounterFailure(t *testing.T) {
+	t.Parallel()
+
+	var errorLog bytes.Buffer
+
+	server, requests := flakyServer(t, "")
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL),
+		datamuseapi.WithQuota(failingQuota{err: errors.New("read-only file system")}),
+		datamuseapi.WithErrorLog(&errorLog))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.NoError(t, err)
+	require.Equal(t, int32(1), requests.Load())
+	require.Equal(t, "warning: request not counted: read-only file system\n", errorLog.String())
+}
+
+func TestClient_RateLimit_SpacesRequests(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "")
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), datamuseapi.WithRateLimit(20, 1))
+
+	start := time.Now()
+
+	for range 3 {
+		_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, int32(3), requests.Load())
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+// closeErrorBody is a response body whose Close fails.
+type closeErrorBody struct {
+	io.Reader
+}
+
+func (closeErrorBody) Close() error {
+	return errors.New("connection reset")
+}
+
+// closeErrorTransport answers every request with a closeErrorBody.
+type closeErrorTransport struct{}
+
+func (closeErrorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       closeErrorBody{strings.NewReader(`[{"word":"sea"}]`)},
+	}, nil
+}
+
+// TestClient_ErrorLog verifies that diagnostics such as a failure to
+// close the response body go to the error log, not to the results.
+func TestClient_ErrorLog(t *testing.T) {
+	t.Parallel()
+
+	var errorLog bytes.Buffer
+
+	client := datamuseapi.NewClient(
+		datamuseapi.WithTransport(closeErrorTransport{}),
+		datamuseapi.WithErrorLog(&errorLog),
+	)
+
+	results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "error closing response body: connection reset\n", errorLog.String())
+}
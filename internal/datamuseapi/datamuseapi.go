@@ -4,10 +4,8 @@ package datamuseapi
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -15,25 +13,76 @@ import (
 	"time"
 )
 
-// RequestTimeout defines the maximum duration allowed for API requests,
-// set to ten seconds.
-const RequestTimeout = 10 * time.Second
+// MaxResults is the largest number of results the API returns for a
+// query.
+const MaxResults = 1000
+
+// DefaultTimeout defines the default maximum duration allowed for API
+// requests, set to ten seconds.
+const DefaultTimeout = 10 * time.Second
 
 // QueryParams defines the parameters used for making a query to the
 // Datamuse API.
 type QueryParams struct {
-	Lc         string   `url:"lc,omitempty"`     // Left context
-	Max        int      `url:"max,omitempty"`    // Maximum number of results to return
-	Md         string   `url:"md,omitempty"`     // Metadata flags
-	Ml         bool     `url:"-"`                // If true, performs a "means like" search.
-	Sl         bool     `url:"-"`                // If true, performs a  "Sounds like" search.
-	Sp         bool     `url:"-"`                // If true, performs a "Spelled like" search.
-	Qe         string   `url:"qe,omitempty"`     // Query echo
-	Rc         string   `url:"rc,omitempty"`     // Right context
-	RelCode    []string `url:"rel_,omitempty"`   // Related word constraints with a code.
-	Topics     []string `url:"topics,omitempty"` // Topic words (space or comma delimited).
-	V          string   `url:"v,omitempty"`      // Identifier for the vocabulary to use.
-	SearchTerm string   `url:"search_term"`      // SearchTerm represents the word or phrase to be searched in the API.
+	Lc         string        `url:"lc,omitempty"`     // Left context
+	Max        int           `url:"max,omitempty"`    // Maximum number of results to return
+	Md         string        `url:"md,omitempty"`     // Metadata flags
+	IPA        bool          `url:"ipa,omitempty"`    // Return pronunciations in IPA rather than ARPAbet.
+	Ml         string        `url:"ml,omitempty"`     // Means like constraint.
+	Sl         string        `url:"sl,omitempty"`     // Sounds like constraint.
+	Sp         string        `url:"sp,omitempty"`     // Spelled like constraint.
+	Qe         string        `url:"qe,omitempty"`     // Query echo
+	Rc         string        `url:"rc,omitempty"`     // Right context
+	Rel        []RelatedWord `url:"rel_,omitempty"`   // Related word constraints with a code.
+	Topics     []string      `url:"topics,omitempty"` // Topic words (space or comma delimited).
+	V          string        `url:"v,omitempty"`      // Identifier for the vocabulary to use.
+	SearchTerm string        `url:"-"`                // Default term for related word constraints without their own term.
+}
+
+// RelatedWord is a related word constraint: a relation code such as
+// "syn" and the term the results must be related to. An empty Term
+// means the query's SearchTerm is used.
+type RelatedWord struct {
+	Code RelationCode
+	Term string
+}
+
+// ParseRelatedWord parses a related word constraint written as "code"
+// or "code=term", such as "syn" or "syn=ocean", and validates the code.
+func ParseRelatedWord(value string) (RelatedWord, error) {
+	codeStr, term, _ := strings.Cut(value, "=")
+
+	if strings.TrimSpace(codeStr) == "" {
+		return RelatedWord{}, fmt.Errorf("%w: %q", ErrMissingRelationCode, value)
+	}
+
+	code, err := ParseRelationCode(codeStr)
+	if err != nil {
+		return RelatedWord{}, err
+	}
+
+	return RelatedWord{Code: code, Term: strings.TrimSpace(term)}, nil
+}
+
+// Validate reports an error if the query has no search constraint or a
+// related word constraint has an invalid code or has neither its own
+// term nor a SearchTerm.
+func (q *QueryParams) Validate() error {
+	if q.Ml == "" && q.Sl == "" && q.Sp == "" && len(q.Rel) == 0 {
+		return ErrNoConstraint
+	}
+
+	for _, rel := range q.Rel {
+		if !rel.Code.Valid() {
+			return fmt.Errorf("%w %q", ErrInvalidRelationCode, rel.Code)
+		}
+
+		if rel.Term == "" && q.SearchTerm == "" {
+			return fmt.Errorf("%w: related word %q", ErrMissingTerm, rel.Code)
+		}
+	}
+
+	return nil
 }
 
 // APIResponse holds the response data returned by the Datamuse API.
@@ -45,70 +94,107 @@ type APIResponse struct {
 	NumSyllables  int      `json:"numSyllables"`        // Syllable count
 	Tags          []string `json:"tags"`                // Parts of speech (e.g., noun, verb, adj)
 	Definitions   []string `json:"defs"`                // Definitions
-	Pronunciation string   `json:"pron,omitempty"`      // Pronunciation (extracted from tags)
+	Pronunciation string   `json:"pron,omitempty"`      // ARPAbet pronunciation (extracted from tags)
 	Frequency     float64  `json:"frequency,omitempty"` // Word frequency (extracted from tags)
 	QueryURL      string   `json:"queryURL,omitempty"`  // The API query URL
+
+	// IPAPronunciation is the pronunciation in IPA as given by Datamuse
+	// when the query sets ipa=1 (extracted from tags).
+	IPAPronunciation string `json:"ipaPron,omitempty"`
 }
 
-// ErrAPIError is a package-level error for API failures.
-var ErrAPIError = errors.New("datamuse api error")
+var (
+	// ErrAPIError is a package-level error for API failures.
+	ErrAPIError = errors.New("datamuse api error")
+	// ErrQuotaExceeded is wrapped by a QuotaTracker error to refuse a
+	// request because the daily request limit has been reached.
+	ErrQuotaExceeded = errors.New("daily request limit reached")
+	// ErrNoConstraint reports a query without any means-like,
+	// sounds-like, spelled-like or related word constraint.
+	ErrNoConstraint = errors.New("at least one of means-like, sounds-like, spelled-like or related-word is required")
+	// ErrMissingTerm reports a constraint that has no term to search for.
+	ErrMissingTerm = errors.New("no search term provided")
+	// ErrMissingRelationCode reports a related word constraint without
+	// a relation code.
+	ErrMissingRelationCode = errors.New("missing relation code")
+)
 
-// buildQueryURL constructs the URL for querying the Datamuse API based
-// on QueryParams.
-func (q *QueryParams) buildQueryURL() string {
-	baseURL := "https://api.datamuse.com/words"
+// urlBuilder builds the URL of a request to an endpoint of the Datamuse
+// API, with the query parameters in the order they are added.
+type urlBuilder struct {
+	builder strings.Builder
+}
 
-	var builder strings.Builder
+// newURLBuilder starts the URL of the endpoint at path relative to
+// baseURL.
+func newURLBuilder(baseURL, path string) *urlBuilder {
+	b := &urlBuilder{}
+	b.builder.WriteString(strings.TrimSuffix(baseURL, "/") + path + "?")
 
-	builder.WriteString(baseURL + "?")
+	return b
+}
 
-	appendParam := func(key, value string) {
-		if value != "" {
-			builder.WriteString(fmt.Sprintf("%s=%s&", url.QueryEscape(key), url.QueryEscape(value)))
-		}
+// appendParam adds a query parameter unless its value is empty.
+func (b *urlBuilder) appendParam(key, value string) {
+	if value != "" {
+		b.builder.WriteString(fmt.Sprintf("%s=%s&", url.QueryEscape(key), url.QueryEscape(value)))
 	}
+}
 
-	// Append the correct search parameter based on which boolean is true.
-	if q.Ml {
-		appendParam("ml", q.SearchTerm)
-	}
+// String returns the URL built so far.
+func (b *urlBuilder) String() string {
+	return strings.TrimSuffix(b.builder.String(), "&")
+}
 
-	if q.Sl {
-		appendParam("sl", q.SearchTerm)
-	}
+// buildQueryURL constructs the URL for querying the words endpoint of
+// the Datamuse API at baseURL based on QueryParams.
+func (q *QueryParams) buildQueryURL(baseURL string) string {
+	builder := newURLBuilder(baseURL, wordsPath)
+	appendParam := builder.appendParam
 
-	if q.Sp {
-		appendParam("sp", q.SearchTerm)
-	}
+	// Append each search constraint that has a term.
+	appendParam("ml", q.Ml)
+	appendParam("sl", q.Sl)
+	appendParam("sp", q.Sp)
 
 	appendParam("v", q.V)
 	appendParam("lc", q.Lc)
 	appendParam("rc", q.Rc)
 	appendParam("md", q.Md)
+
+	if q.IPA {
+		appendParam("ipa", "1")
+	}
+
 	appendParam("qe", q.Qe)
 
 	if len(q.Topics) > 0 {
 		appendParam("topics", strings.Join(q.Topics, ","))
 	}
 
-	// When using rel, append SearchTerm to each related word constraint.
-	for _, rel := range q.RelCode {
-		appendParam("rel_"+rel, q.SearchTerm)
+	// Append each related word constraint, falling back to SearchTerm
+	// when the constraint has no term of its own.
+	for _, rel := range q.Rel {
+		term := rel.Term
+		if term == "" {
+			term = q.SearchTerm
+		}
+
+		appendParam("rel_"+string(rel.Code), term)
 	}
 
 	if q.Max > 0 {
 		appendParam("max", strconv.Itoa(q.Max))
 	}
 
-	queryURL := strings.TrimSuffix(builder.String(), "&")
-
-	return queryURL
+	return builder.String()
 }
 
 // parseAPIResponse processes the raw API response and extracts additional
 // fields like pronunciation, frequency, and the original query URL.
 func parseAPIResponse(rawResponse []APIResponse, queryURL string) []APIResponse {
 	for i, result := range rawResponse {
+		result.IPAPronunciation, result.Tags = extractTagPrefix(result.Tags, "ipa_pron:")
 		result.Pronunciation, result.Tags = extractPronunciation(result.Tags)
 		result.Frequency, result.Tags = extractFrequency(result.Tags)
 		result.QueryURL = queryURL // Set the query URL in the response.
@@ -153,63 +239,15 @@ func extractFrequency(tags []string) (float64, []string) {
 }
 
 // QueryAPI sends a request to the Datamuse API based on the provided
-// query parameters and returns the parsed API response or an error.
+// query parameters and returns the parsed API response or an error. It
+// is a thin wrapper around Client.Query using the default base URL and
+// the provided HTTP client.
 func QueryAPI(queryParams QueryParams, client *http.Client) ([]APIResponse, error) {
-	// Build the query URL.
-	queryURL := queryParams.buildQueryURL()
-
-	// Parse and validate the queryURL.
-	parsedURL, err := url.Parse(queryURL)
-	if err != nil || !parsedURL.IsAbs() {
-		return nil, fmt.Errorf("%w: invalid URL: %s", ErrAPIError, queryURL)
-	}
-
-	// Create a request context with a timeout to limit the duration of
-	// the API request.
-	ctx, cancel := context.WithTimeout(context.Background(), RequestTimeout)
-	defer cancel()
-
-	// Build an HTTP GET request with the specified context and query URL.
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to create request: %w", ErrAPIError, err)
-	}
-
-	// Send the request using the provided client.
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to send request: %w", ErrAPIError, err)
-	}
-
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			fmt.Printf("error closing response body: %v\n", closeErr)
-		}
-	}()
-
-	// Check if the response status is '200 OK' to ensure a successful
-	// request.
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%w: unexpected response code: %d", ErrAPIError, resp.StatusCode)
-	}
-
-	// Read the response body into a byte slice for further processing.
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to read response body: %w", ErrAPIError, err)
-	}
-
-	// Unmarshal the response body from JSON into the APIResponse slice.
-	var apiResponses []APIResponse
-
-	err = json.Unmarshal(body, &apiResponses)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to parse JSON: %w", ErrAPIError, err)
-	}
-
-	// Parse the API response to extract pronunciation, frequency, and
-	// the query URL for each result.
-	parsedResponses := parseAPIResponse(apiResponses, queryURL)
+	return NewClient(WithHTTPClient(client)).Query(queryParams)
+}
 
-	return parsedResponses, nil
+// QueryAPIContext is like QueryAPI but sends the request with the given
+// context.
+func QueryAPIContext(ctx context.Context, queryParams QueryParams, client *http.Client) ([]APIResponse, error) {
+	return NewClient(WithHTTPClient(client)).QueryContext(ctx, queryParams)
 }
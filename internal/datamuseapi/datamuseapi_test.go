@@ -22,8 +22,7 @@ func TestQueryAPI_NoResults(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		SearchTerm: "thststrtrnsnthng",
+		Sp: "thststrtrnsnthng",
 	}
 
 	// Execute QueryAPI
@@ -47,9 +46,8 @@ func TestQueryAPI_SuccessfulSpelledLike(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		Max:        3,
-		SearchTerm: "pyro*",
+		Sp:  "pyro*",
+		Max: 3,
 	}
 
 	// Execute QueryAPI
@@ -76,9 +74,8 @@ func TestQueryAPI_SuccessfulSoundsLike(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sl:         true,
-		Max:        3,
-		SearchTerm: "pie row",
+		Sl:  "pie row",
+		Max: 3,
 	}
 
 	// Execute QueryAPI
@@ -104,9 +101,8 @@ func TestQueryAPI_SuccessfulMeansLike(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Ml:         true,
-		Max:        3,
-		SearchTerm: "pyro",
+		Ml:  "pyro",
+		Max: 3,
 	}
 
 	// Execute QueryAPI
@@ -132,7 +128,7 @@ func TestQueryAPI_SuccessfulRelatedAntonym(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		RelCode:    []string{"ant"},
+		Rel:        []datamuseapi.RelatedWord{{Code: datamuseapi.RelAntonym}},
 		Max:        3,
 		SearchTerm: "fire",
 	}
@@ -188,10 +184,9 @@ func TestQueryAPI_SuccessfulMetadataSearch(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Ml:         true,
-		Md:         "dfprs", // Metadata flags
-		Max:        2,
-		SearchTerm: "burn",
+		Ml:  "burn",
+		Md:  "dfprs", // Metadata flags
+		Max: 2,
 	}
 
 	// Execute QueryAPI
@@ -222,8 +217,7 @@ func TestQueryAPI_SuccessfulMetadataSearch(t *testing.T) {
 // func TestQueryAPI_InvalidURL(t *testing.T) {
 // 	// Define invalid query parameters
 // 	queryParams := datamuseapi.QueryParams{
-// 		SearchTerm: " ", // This will generate an invalid URL
-// 		Sp:         true,
+// 		Sp: " ", // This will generate an invalid URL
 // 	}
 
 // 	// Execute QueryAPI
@@ -249,8 +243,7 @@ func TestQueryAPI_RequestSendFailure(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		SearchTerm: "validterm",
+		Sp: "validterm",
 	}
 
 	// Execute QueryAPI
@@ -279,8 +272,7 @@ func TestQueryAPI_HTTPClientFailure(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		SearchTerm: "pyro",
+		Sp: "pyro",
 	}
 
 	// Execute QueryAPI
@@ -307,8 +299,7 @@ func TestQueryAPI_Non200Response(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		SearchTerm: "pyro",
+		Sp: "pyro",
 	}
 
 	// Execute QueryAPI
@@ -332,8 +323,7 @@ func TestQueryAPI_ResponseBodyReadError(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		SearchTerm: "pyro",
+		Sp: "pyro",
 	}
 
 	// Execute QueryAPI
@@ -357,8 +347,7 @@ func TestQueryAPI_InvalidJSONResponse(t *testing.T) {
 
 	// Define query parameters
 	queryParams := datamuseapi.QueryParams{
-		Sp:         true,
-		SearchTerm: "pyro",
+		Sp: "pyro",
 	}
 
 	// Execute QueryAPI
@@ -370,3 +359,92 @@ func TestQueryAPI_InvalidJSONResponse(t *testing.T) {
 	require.ErrorContains(t, err, "failed to parse JSON")
 	require.Nil(t, results)
 }
+
+//nolint:paralleltest
+func TestQueryAPI_CombinedConstraints(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// Mock URL and response for a means-like search restricted by spelling
+	// and a related word constraint with its own term.
+	//nolint:lll
+	httpmock.RegisterResponder("GET", "https://api.datamuse.com/words?ml=ocean&sp=s%2A&rel_trg=beach&max=2",
+		httpmock.NewStringResponder(200, `[{"word":"sea","score":1001},{"word":"surf","score":998}]`))
+
+	// Define query parameters
+	queryParams := datamuseapi.QueryParams{
+		Ml:  "ocean",
+		Sp:  "s*",
+		Rel: []datamuseapi.RelatedWord{{Code: datamuseapi.RelTrigger, Term: "beach"}},
+		Max: 2,
+	}
+
+	// Execute QueryAPI
+	client := &http.Client{}
+	results, err := datamuseapi.QueryAPI(queryParams, client)
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, "sea", results[0].Word)
+	require.Equal(t, "surf", results[1].Word)
+}
+
+func TestParseRelatedWord(t *testing.T) {
+	t.Parallel()
+
+	rel, err := datamuseapi.ParseRelatedWord("syn")
+	require.NoError(t, err)
+	require.Equal(t, datamuseapi.RelatedWord{Code: datamuseapi.RelSynonym}, rel)
+
+	rel, err = datamuseapi.ParseRelatedWord("trg=sandy beach")
+	require.NoError(t, err)
+	require.Equal(t, datamuseapi.RelatedWord{Code: datamuseapi.RelTrigger, Term: "sandy beach"}, rel)
+
+	_, err = datamuseapi.ParseRelatedWord("=ocean")
+	require.ErrorIs(t, err, datamuseapi.ErrMissingRelationCode)
+
+	_, err = datamuseapi.ParseRelatedWord("snn=joy")
+	require.ErrorIs(t, err, datamuseapi.ErrInvalidRelationCode)
+}
+
+func TestQueryParams_Validate(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, (&datamuseapi.QueryParams{}).Validate(), datamuseapi.ErrNoConstraint)
+	require.NoError(t, (&datamuseapi.QueryParams{Ml: "ocean"}).Validate())
+
+	rel := []datamuseapi.RelatedWord{{Code: datamuseapi.RelSynonym}}
+	require.ErrorIs(t, (&datamuseapi.QueryParams{Rel: rel}).Validate(), datamuseapi.ErrMissingTerm)
+	require.NoError(t, (&datamuseapi.QueryParams{Rel: rel, SearchTerm: "joy"}).Validate())
+}
+
+//nolint:paralleltest
+func TestQueryAPI_IPAPronunciation(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// Mock URL and response for a query asking for IPA pronunciations.
+	httpmock.RegisterResponder("GET", "https://api.datamuse.com/words?sp=joy&md=r&ipa=1&max=1",
+		httpmock.NewStringResponder(200,
+			`[{"word":"joy","score":100,"tags":["pron:JH OY1 ","ipa_pron:dʒˈɔɪ"]}]`))
+
+	// Define query parameters
+	queryParams := datamuseapi.QueryParams{
+		Sp:  "joy",
+		Md:  "r",
+		IPA: true,
+		Max: 1,
+	}
+
+	// Execute QueryAPI
+	client := &http.Client{}
+	results, err := datamuseapi.QueryAPI(queryParams, client)
+
+	// Assertions
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "JH OY1 ", results[0].Pronunciation)
+	require.Equal(t, "dʒˈɔɪ", results[0].IPAPronunciation)
+	require.Empty(t, results[0].Tags)
+}
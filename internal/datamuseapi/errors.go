@@ -0,0 +1,96 @@
+package datamuseapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"unicode/utf8"
+)
+
+// maxErrorBody is the maximum number of bytes of a response body kept in
+// an HTTPStatusError or DecodeError.
+const maxErrorBody = 512
+
+// RequestError reports a request that could not be sent or whose
+// response could not be read, such as a DNS failure, a refused
+// connection or a timeout.
+type RequestError struct {
+	URL string
+	Err error
+}
+
+// Error implements the error interface.
+func (e *RequestError) Error() string {
+	return fmt.Sprintf("%v: failed to send request: %v", ErrAPIError, e.Err)
+}
+
+// Unwrap returns ErrAPIError and the underlying error.
+func (e *RequestError) Unwrap() []error {
+	return []error{ErrAPIError, e.Err}
+}
+
+// Timeout reports whether the request failed because a deadline was
+// exceeded.
+func (e *RequestError) Timeout() bool {
+	var netErr net.Error
+
+	return errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &netErr) && netErr.Timeout())
+}
+
+// HTTPStatusError reports a response with a status other than 200 OK.
+type HTTPStatusError struct {
+	StatusCode int
+	URL        string
+	Body       string // Response body, truncated to a few hundred bytes.
+}
+
+// Error implements the error interface.
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%v: unexpected response code: %d", ErrAPIError, e.StatusCode)
+}
+
+// Unwrap returns ErrAPIError.
+func (e *HTTPStatusError) Unwrap() error {
+	return ErrAPIError
+}
+
+// DecodeError reports a response body that is not valid Datamuse JSON.
+type DecodeError struct {
+	URL  string
+	Body string // Response body, truncated to a few hundred bytes.
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%v: failed to parse JSON: %v", ErrAPIError, e.Err)
+}
+
+// Unwrap returns ErrAPIError and the underlying error.
+func (e *DecodeError) Unwrap() []error {
+	return []error{ErrAPIError, e.Err}
+}
+
+// IsTimeout reports whether err was caused by a request exceeding its
+// deadline, whether set with WithTimeout or by the caller's context.
+func IsTimeout(err error) bool {
+	var requestErr *RequestError
+
+	return errors.As(err, &requestErr) && requestErr.Timeout()
+}
+
+// truncateBody returns body as a string of at most maxErrorBody bytes,
+// cut on a rune boundary and marked with an ellipsis when shortened.
+func truncateBody(body []byte) string {
+	if len(body) <= maxErrorBody {
+		return string(body)
+	}
+
+	cut := maxErrorBody
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+
+	return string(body[:cut]) + "…"
+}
@@ -0,0 +1,102 @@
+package datamuseapi_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer returns a test server that answers every request with
+// the given status code and body.
+func newTestServer(t *testing.T, statusCode int, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(statusCode)
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestClient_Query_HTTPStatusError(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, http.StatusInternalServerError, strings.Repeat("x", 2000))
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	var statusErr *datamuseapi.HTTPStatusError
+
+	require.ErrorAs(t, err, &statusErr)
+	require.ErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+	require.Equal(t, server.URL+"/words?ml=ocean", statusErr.URL)
+	require.Less(t, len(statusErr.Body), 600)
+	require.True(t, strings.HasSuffix(statusErr.Body, "…"))
+	require.False(t, datamuseapi.IsTimeout(err))
+}
+
+func TestClient_Query_DecodeError(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, http.StatusOK, `{"invalid json"}`)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	var decodeErr *datamuseapi.DecodeError
+
+	require.ErrorAs(t, err, &decodeErr)
+	require.ErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.Equal(t, `{"invalid json"}`, decodeErr.Body)
+	require.ErrorContains(t, err, "failed to parse JSON")
+}
+
+func TestClient_Query_TimeoutError(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), datamuseapi.WithTimeout(20*time.Millisecond))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	var requestErr *datamuseapi.RequestError
+
+	require.ErrorAs(t, err, &requestErr)
+	require.True(t, datamuseapi.IsTimeout(err))
+}
+
+func TestClient_Query_ConnectionError(t *testing.T) {
+	t.Parallel()
+
+	server := newTestServer(t, http.StatusOK, `[]`)
+	server.Close()
+
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	var requestErr *datamuseapi.RequestError
+
+	require.ErrorAs(t, err, &requestErr)
+	require.Equal(t, server.URL+"/words?ml=ocean", requestErr.URL)
+	require.False(t, datamuseapi.IsTimeout(err))
+	require.False(t, errors.As(err, new(*datamuseapi.HTTPStatusError)))
+}
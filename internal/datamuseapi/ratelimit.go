@@ -0,0 +1,95 @@
+package datamuseapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QuotaTracker is consulted before every request sent to the API;
+// responses served from the cache are not counted. Returning an error
+// wrapping ErrQuotaExceeded prevents the request, while any other error,
+// such as a failure to update a request counter, is written to the error
+// log and the request is sent. Implementations must be safe for
+// concurrent use.
+type QuotaTracker interface {
+	Acquire() error
+}
+
+// rateLimiter is a token bucket that allows bursts of up to burst
+// requests and refills at rate tokens per second.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// WithRateLimit limits the client to requestsPerSecond requests per
+// second on average, allowing bursts of up to burst requests. A rate of
+// zero or less disables the limit.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(c *Client) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+
+			return
+		}
+
+		burstTokens := float64(max(burst, 1))
+
+		c.limiter = &rateLimiter{
+			rate:   requestsPerSecond,
+			burst:  burstTokens,
+			tokens: burstTokens,
+			last:   time.Now(),
+		}
+	}
+}
+
+// WithQuota sets the tracker consulted before every request sent to the
+// API.
+func WithQuota(quota QuotaTracker) Option {
+	return func(c *Client) {
+		c.quota = quota
+	}
+}
+
+// wait blocks until a token is available or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return ctx.Err() //nolint:wrapcheck
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available at now and returns zero, or
+// returns how long to wait until the next token is available.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
@@ -0,0 +1,149 @@
+package datamuseapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RelationCode identifies a Datamuse related word constraint, sent as
+// the "rel_<code>" query parameter.
+type RelationCode string
+
+// Relation codes supported by the Datamuse API.
+const (
+	RelPopularNouns      RelationCode = "jja" // Popular nouns modified by the given adjective
+	RelPopularAdjectives RelationCode = "jjb" // Popular adjectives used to modify the given noun
+	RelSynonym           RelationCode = "syn" // Synonyms
+	RelTrigger           RelationCode = "trg" // Words statistically associated with the query word
+	RelAntonym           RelationCode = "ant" // Antonyms
+	RelKindOf            RelationCode = "spc" // Direct hypernyms
+	RelMoreGeneral       RelationCode = "gen" // Direct hyponyms
+	RelComprises         RelationCode = "com" // Direct holonyms
+	RelPartOf            RelationCode = "par" // Direct meronyms
+	RelFollower          RelationCode = "bga" // Frequent followers
+	RelPredecessor       RelationCode = "bgb" // Frequent predecessors
+	RelRhyme             RelationCode = "rhy" // Perfect rhymes
+	RelNearRhyme         RelationCode = "nry" // Approximate rhymes
+	RelHomophone         RelationCode = "hom" // Homophones
+	RelConsonantMatch    RelationCode = "cns" // Consonant match
+)
+
+// ErrInvalidRelationCode reports a relation code that Datamuse does not
+// support.
+var ErrInvalidRelationCode = errors.New("invalid relation code")
+
+// maxSuggestionDistance is the largest edit distance at which an invalid
+// relation code is still considered a typo of a valid one.
+const maxSuggestionDistance = 2
+
+// RelationInfo describes a relation code for help output.
+type RelationInfo struct {
+	Code        RelationCode
+	Description string
+	Example     string
+}
+
+// relations lists every supported relation code in the order used by
+// the Datamuse documentation.
+//
+//nolint:gochecknoglobals,lll
+var relations = []RelationInfo{
+	{RelPopularNouns, "Popular nouns modified by the given adjective, per Google Books Ngrams", "gradual → increase"},
+	{RelPopularAdjectives, "Popular adjectives used to modify the given noun, per Google Books Ngrams", "beach → sandy"},
+	{RelSynonym, "Synonyms (words contained within the same WordNet synset)", "ocean → sea"},
+	{RelTrigger, `"Triggers" (words statistically associated with the query word in the same text)`, "cow → milking"},
+	{RelAntonym, "Antonyms (per WordNet)", "late → early"},
+	{RelKindOf, `"Kind of" (direct hypernyms, per WordNet)`, "gondola → boat"},
+	{RelMoreGeneral, `"More general than" (direct hyponyms, per WordNet)`, "boat → gondola"},
+	{RelComprises, `"Comprises" (direct holonyms, per WordNet)`, "car → accelerator"},
+	{RelPartOf, `"Part of" (direct meronyms, per WordNet)`, "trunk → tree"},
+	{RelFollower, "Frequent followers, per Google Books Ngrams", "wreak → havoc"},
+	{RelPredecessor, "Frequent predecessors, per Google Books Ngrams", "havoc → wreak"},
+	{RelRhyme, "Rhymes (perfect rhymes, per RhymeZone)", "spade → aid"},
+	{RelNearRhyme, "Approximate rhymes, per RhymeZone", "forest → chorus"},
+	{RelHomophone, "Homophones (sound-alike words)", "course → coarse"},
+	{RelConsonantMatch, "Consonant match", "sample → simple"},
+}
+
+// Relations returns a description of every supported relation code.
+func Relations() []RelationInfo {
+	return append([]RelationInfo(nil), relations...)
+}
+
+// Description returns the description of the relation code, or an empty
+// string if the code is not supported.
+func (r RelationCode) Description() string {
+	for _, info := range relations {
+		if info.Code == r {
+			return info.Description
+		}
+	}
+
+	return ""
+}
+
+// Valid reports whether the relation code is supported by Datamuse.
+func (r RelationCode) Valid() bool {
+	return r.Description() != ""
+}
+
+// ParseRelationCode converts a string such as "syn" to a RelationCode.
+// An unsupported code produces an error wrapping ErrInvalidRelationCode
+// that suggests the closest valid code when there is one.
+func ParseRelationCode(value string) (RelationCode, error) {
+	code := RelationCode(strings.ToLower(strings.TrimSpace(value)))
+	if code.Valid() {
+		return code, nil
+	}
+
+	if suggestion, ok := closestRelationCode(string(code)); ok {
+		return "", fmt.Errorf("%w %q (did you mean %q?)", ErrInvalidRelationCode, value, suggestion)
+	}
+
+	return "", fmt.Errorf("%w %q (run 'polyhymnia relations' to list valid codes)", ErrInvalidRelationCode, value)
+}
+
+// closestRelationCode returns the valid relation code with the smallest
+// edit distance to value, if that distance is small enough to be a typo.
+func closestRelationCode(value string) (RelationCode, bool) {
+	var (
+		best         RelationCode
+		bestDistance = maxSuggestionDistance + 1
+	)
+
+	for _, info := range relations {
+		if distance := editDistance(value, string(info.Code)); distance < bestDistance {
+			best, bestDistance = info.Code, distance
+		}
+	}
+
+	return best, bestDistance <= maxSuggestionDistance
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
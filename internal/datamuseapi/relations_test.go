@@ -0,0 +1,57 @@
+package datamuseapi_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelationCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected datamuseapi.RelationCode
+		errText  string
+	}{
+		{name: "Valid code", input: "syn", expected: datamuseapi.RelSynonym},
+		{name: "Upper case code", input: "RHY", expected: datamuseapi.RelRhyme},
+		{name: "Near rhyme code", input: "nry", expected: datamuseapi.RelNearRhyme},
+		{name: "Typo suggests closest code", input: "snn", errText: `invalid relation code "snn" (did you mean "syn"?)`},
+		{name: "Transposed letters", input: "tgr", errText: `(did you mean "trg"?)`},
+		{name: "Unrelated input", input: "banana", errText: "polyhymnia relations"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			code, err := datamuseapi.ParseRelationCode(testCase.input)
+
+			if testCase.errText != "" {
+				require.ErrorIs(t, err, datamuseapi.ErrInvalidRelationCode)
+				require.ErrorContains(t, err, testCase.errText)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, testCase.expected, code)
+		})
+	}
+}
+
+func TestRelations_CoversEveryCode(t *testing.T) {
+	t.Parallel()
+
+	relations := datamuseapi.Relations()
+	require.Len(t, relations, 15)
+
+	for _, info := range relations {
+		require.True(t, info.Code.Valid(), info.Code)
+		require.NotEmpty(t, info.Description, info.Code)
+		require.NotEmpty(t, info.Example, info.Code)
+	}
+}
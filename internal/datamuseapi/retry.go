@@ -0,0 +1,145 @@
+package datamuseapi
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for the retry policy used by the command-line interface.
+const (
+	DefaultMaxAttempts = 3
+	DefaultRetryDelay  = 500 * time.Millisecond
+	DefaultMaxDelay    = 10 * time.Second
+	// DefaultMaxRetryAfter is the longest Retry-After delay honored when
+	// the policy does not set MaxRetryAfter.
+	DefaultMaxRetryAfter = time.Minute
+)
+
+// RetryPolicy controls how a Client retries requests that fail with a
+// transient error: a transport error or a 429, 500, 502, 503 or 504
+// response. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below two disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles for
+	// each further retry, with random jitter of up to half the delay.
+	BaseDelay time.Duration
+	// MaxDelay caps the computed backoff delay. A delay requested by
+	// the server with Retry-After is honored even when it is longer.
+	MaxDelay time.Duration
+	// MaxRetryAfter is the longest delay requested with Retry-After
+	// that is waited for; the client gives up on a longer one rather
+	// than hang. Zero means DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+}
+
+// retryHint describes whether a failed attempt may be retried and how
+// long the server asked the client to wait before doing so.
+type retryHint struct {
+	retryable bool
+	after     time.Duration
+}
+
+// WithRetry sets the policy for retrying transient failures.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// fetchWithRetry calls fetch until it succeeds, fails with an error that
+// is not transient, the retry policy's attempts are used up or the server
+// asks to wait longer than the policy's MaxRetryAfter. Waiting
+// between attempts stops early when ctx is done, returning the last
+// failure joined with the context's error.
+func (c *Client) fetchWithRetry(ctx context.Context, queryURL string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, hint, err := c.fetch(ctx, queryURL)
+		if err == nil || !hint.retryable || attempt >= c.retry.MaxAttempts ||
+			hint.after > c.retry.maxRetryAfter() {
+			return body, err
+		}
+
+		delay := max(c.retry.backoff(attempt), hint.after)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// maxRetryAfter returns the longest Retry-After delay to wait for.
+func (p RetryPolicy) maxRetryAfter() time.Duration {
+	if p.MaxRetryAfter <= 0 {
+		return DefaultMaxRetryAfter
+	}
+
+	return p.MaxRetryAfter
+}
+
+// backoff returns the delay before the retry that follows the given
+// attempt: BaseDelay doubled for each earlier retry, capped at MaxDelay,
+// with the upper half randomized to spread out concurrent clients.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if half := delay / 2; half > 0 {
+		delay = half + rand.N(half+1) //nolint:gosec
+	}
+
+	return delay
+}
+
+// isRetryableStatus reports whether a response status indicates a
+// transient failure worth retrying.
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number
+// of seconds or as an HTTP date relative to now. It returns zero when the
+// header is absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
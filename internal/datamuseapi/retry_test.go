@@ -0,0 +1,155 @@
+package datamuseapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyServer returns a test server that answers the first len(statuses)
+// requests with the given status codes and every later request with a
+// successful response. It counts the requests it receives.
+func flakyServer(t *testing.T, retryAfter string, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := int(requests.Add(1))
+		if n <= len(statuses) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+
+			w.WriteHeader(statuses[n-1])
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`[{"word":"sea","score":100}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+// fastRetry is a retry policy with delays short enough for tests.
+func fastRetry(maxAttempts int) datamuseapi.Option {
+	return datamuseapi.WithRetry(datamuseapi.RetryPolicy{
+		MaxAttempts: maxAttempts,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	})
+}
+
+func TestClient_Retry_RecoversFromTransientFailures(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "", http.StatusBadGateway, http.StatusServiceUnavailable)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), fastRetry(3))
+
+	results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, int32(3), requests.Load())
+}
+
+func TestClient_Retry_GivesUpAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "", http.StatusTooManyRequests, http.StatusTooManyRequests,
+		http.StatusTooManyRequests)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), fastRetry(2))
+
+	results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.ErrorContains(t, err, "unexpected response code: 429")
+	require.Nil(t, results)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestClient_Retry_DoesNotRetryPermanentFailures(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "", http.StatusNotFound)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), fastRetry(3))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.ErrorContains(t, err, "unexpected response code: 404")
+	require.Equal(t, int32(1), requests.Load())
+}
+
+func TestClient_Retry_HonorsRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "1", http.StatusServiceUnavailable)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), fastRetry(2))
+
+	start := time.Now()
+	results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, int32(2), requests.Load())
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestClient_Retry_GivesUpOnLongRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "86400", http.StatusServiceUnavailable)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL), fastRetry(2))
+
+	start := time.Now()
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.ErrorContains(t, err, "unexpected response code: 503")
+	require.Equal(t, int32(1), requests.Load())
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_Retry_RetriesTimeouts(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+
+	// The first request outlasts the client timeout.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			<-r.Context().Done()
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`[{"word":"sea","score":100}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL),
+		datamuseapi.WithTimeout(50*time.Millisecond), fastRetry(2))
+
+	results, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, int32(2), requests.Load())
+}
+
+func TestClient_Retry_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	server, requests := flakyServer(t, "", http.StatusServiceUnavailable)
+	client := datamuseapi.NewClient(datamuseapi.WithBaseURL(server.URL))
+
+	_, err := client.Query(datamuseapi.QueryParams{Ml: "ocean"})
+
+	require.ErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.Equal(t, int32(1), requests.Load())
+}
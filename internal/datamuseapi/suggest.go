@@ -0,0 +1,64 @@
+package datamuseapi
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// suggestPath is the path of the Datamuse autocomplete endpoint relative
+// to the base URL.
+const suggestPath = "/sug"
+
+// SuggestParams defines the parameters used for making a query to the
+// Datamuse autocomplete (/sug) endpoint.
+type SuggestParams struct {
+	S   string `url:"s"`             // Prefix hint string typed by the user
+	Max int    `url:"max,omitempty"` // Maximum number of suggestions to return
+	V   string `url:"v,omitempty"`   // Identifier for the vocabulary to use.
+}
+
+// buildSuggestURL constructs the URL for querying the autocomplete
+// endpoint of the Datamuse API at baseURL based on SuggestParams.
+func (s *SuggestParams) buildSuggestURL(baseURL string) string {
+	builder := newURLBuilder(baseURL, suggestPath)
+	builder.appendParam("s", s.S)
+	builder.appendParam("v", s.V)
+
+	if s.Max > 0 {
+		builder.appendParam("max", strconv.Itoa(s.Max))
+	}
+
+	return builder.String()
+}
+
+// Suggest sends a request to the autocomplete endpoint based on the
+// provided parameters and returns the suggested words or an error.
+func (c *Client) Suggest(suggestParams SuggestParams) ([]APIResponse, error) {
+	return c.SuggestContext(context.Background(), suggestParams)
+}
+
+// SuggestContext is like Suggest but sends the request with the given
+// context.
+func (c *Client) SuggestContext(ctx context.Context, suggestParams SuggestParams) ([]APIResponse, error) {
+	queryURL := suggestParams.buildSuggestURL(c.baseURL)
+
+	apiResponses, err := c.get(ctx, queryURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseAPIResponse(apiResponses, queryURL), nil
+}
+
+// SuggestAPI sends a request to the Datamuse autocomplete endpoint using
+// the default base URL and the provided HTTP client.
+func SuggestAPI(suggestParams SuggestParams, client *http.Client) ([]APIResponse, error) {
+	return NewClient(WithHTTPClient(client)).Suggest(suggestParams)
+}
+
+// SuggestAPIContext is like SuggestAPI but sends the request with the
+// given context.
+func SuggestAPIContext(ctx context.Context, suggestParams SuggestParams, client *http.Client) ([]APIResponse, error) {
+	return NewClient(WithHTTPClient(client)).SuggestContext(ctx, suggestParams)
+}
@@ -0,0 +1,75 @@
+package datamuseapi_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest
+func TestSuggestAPI_Successful(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	//nolint:lll
+	httpmock.RegisterResponder("GET", "https://api.datamuse.com/sug?s=rawh&v=enwiki&max=3",
+		httpmock.NewStringResponder(200, `[{"word":"rawhide","score":2146},{"word":"rawhead","score":1095},{"word":"rawhides","score":632}]`))
+
+	suggestParams := datamuseapi.SuggestParams{
+		S:   "rawh",
+		Max: 3,
+		V:   "enwiki",
+	}
+
+	client := &http.Client{}
+	results, err := datamuseapi.SuggestAPI(suggestParams, client)
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	require.Equal(t, "rawhide", results[0].Word)
+	require.Equal(t, 2146, results[0].Score)
+	require.Equal(t, "https://api.datamuse.com/sug?s=rawh&v=enwiki&max=3", results[0].QueryURL)
+}
+
+//nolint:paralleltest
+func TestSuggestAPIContext_Canceled(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// The responder waits until the request is canceled.
+	httpmock.RegisterResponder("GET", "https://api.datamuse.com/sug?s=rawh",
+		func(req *http.Request) (*http.Response, error) {
+			<-req.Context().Done()
+
+			return nil, req.Context().Err()
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	results, err := datamuseapi.SuggestAPIContext(ctx, datamuseapi.SuggestParams{S: "rawh"}, &http.Client{})
+
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, results)
+}
+
+//nolint:paralleltest
+func TestSuggestAPI_Non200Response(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "https://api.datamuse.com/sug?s=rawh",
+		httpmock.NewStringResponder(500, "Internal Server Error"))
+
+	client := &http.Client{}
+	results, err := datamuseapi.SuggestAPI(datamuseapi.SuggestParams{S: "rawh"}, client)
+
+	require.ErrorIs(t, err, datamuseapi.ErrAPIError)
+	require.ErrorContains(t, err, "unexpected response code: 500")
+	require.Nil(t, results)
+}
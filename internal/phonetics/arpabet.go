@@ -0,0 +1,226 @@
+package phonetics
+
+import (
+	"strings"
+)
+
+// phone describes an ARPAbet phoneme in the other notations.
+type phone struct {
+	ipa     string
+	respell string
+	vowel   bool
+}
+
+// phones maps ARPAbet phonemes, without stress digits, to IPA and
+// respelling. AH and ER are written differently when unstressed; see
+// reducedIPA.
+//
+//nolint:gochecknoglobals
+var phones = map[string]phone{
+	"AA": {"ɑ", "ah", true},
+	"AE": {"æ", "a", true},
+	"AH": {"ʌ", "uh", true},
+	"AO": {"ɔ", "aw", true},
+	"AW": {"aʊ", "ow", true},
+	"AY": {"aɪ", "y", true},
+	"EH": {"ɛ", "eh", true},
+	"ER": {"ɝ", "ur", true},
+	"EY": {"eɪ", "ay", true},
+	"IH": {"ɪ", "ih", true},
+	"IY": {"i", "ee", true},
+	"OW": {"oʊ", "oh", true},
+	"OY": {"ɔɪ", "oy", true},
+	"UH": {"ʊ", "uu", true},
+	"UW": {"u", "oo", true},
+	"B":  {"b", "b", false},
+	"CH": {"tʃ", "ch", false},
+	"D":  {"d", "d", false},
+	"DH": {"ð", "dh", false},
+	"F":  {"f", "f", false},
+	"G":  {"ɡ", "g", false},
+	"HH": {"h", "h", false},
+	"JH": {"dʒ", "j", false},
+	"K":  {"k", "k", false},
+	"L":  {"l", "l", false},
+	"M":  {"m", "m", false},
+	"N":  {"n", "n", false},
+	"NG": {"ŋ", "ng", false},
+	"P":  {"p", "p", false},
+	"R":  {"ɹ", "r", false},
+	"S":  {"s", "s", false},
+	"SH": {"ʃ", "sh", false},
+	"T":  {"t", "t", false},
+	"TH": {"θ", "th", false},
+	"V":  {"v", "v", false},
+	"W":  {"w", "w", false},
+	"Y":  {"j", "y", false},
+	"Z":  {"z", "z", false},
+	"ZH": {"ʒ", "zh", false},
+}
+
+// reducedIPA is the IPA for vowels that change quality when unstressed.
+//
+//nolint:gochecknoglobals
+var reducedIPA = map[string]string{
+	"AH": "ə",
+	"ER": "ɚ",
+}
+
+// onsets lists the consonant clusters of two or more phonemes that may
+// begin an English syllable. Any single consonant but NG may begin one.
+//
+//nolint:gochecknoglobals
+var onsets = map[string]bool{
+	"P R": true, "P L": true, "B R": true, "B L": true, "T R": true, "D R": true,
+	"K R": true, "K L": true, "G R": true, "G L": true, "F R": true, "F L": true,
+	"TH R": true, "SH R": true, "S P": true, "S T": true, "S K": true, "S M": true,
+	"S N": true, "S L": true, "S W": true, "T W": true, "D W": true, "K W": true,
+	"G W": true, "P Y": true, "B Y": true, "F Y": true, "K Y": true, "M Y": true,
+	"HH Y": true, "V Y": true, "S P R": true, "S T R": true, "S K R": true,
+	"S P L": true, "S K W": true, "S K Y": true, "S P Y": true,
+}
+
+// syllable is a syllable of an ARPAbet pronunciation: its phonemes
+// without stress digits and the stress of its vowel.
+type syllable struct {
+	phonemes []string
+	stress   byte
+}
+
+// IsARPAbet reports whether pron is a non-empty ARPAbet pronunciation.
+func IsARPAbet(pron string) bool {
+	phonemes := Phonemes(pron)
+
+	for _, phoneme := range phonemes {
+		if _, ok := phones[strings.TrimRight(phoneme, "012")]; !ok {
+			return false
+		}
+	}
+
+	return len(phonemes) > 0
+}
+
+// syllabify splits an ARPAbet pronunciation into syllables. Consonants
+// between two vowels begin the second syllable as far as they form a
+// valid onset and end the first syllable otherwise.
+func syllabify(pron string) []syllable {
+	var (
+		syllables []syllable
+		cluster   []string // Consonants since the last vowel
+	)
+
+	for _, phoneme := range Phonemes(pron) {
+		base := strings.TrimRight(phoneme, "012")
+
+		if !phones[base].vowel {
+			cluster = append(cluster, base)
+
+			continue
+		}
+
+		split := 0
+		if len(syllables) > 0 {
+			split = onsetStart(cluster)
+			last := &syllables[len(syllables)-1]
+			last.phonemes = append(last.phonemes, cluster[:split]...)
+		}
+
+		stress := byte(Unstressed)
+		if len(base) < len(phoneme) {
+			stress = phoneme[len(phoneme)-1]
+		}
+
+		syllables = append(syllables, syllable{
+			phonemes: append(append([]string{}, cluster[split:]...), base),
+			stress:   stress,
+		})
+		cluster = nil
+	}
+
+	// Trailing consonants close the last syllable.
+	if len(syllables) > 0 {
+		last := &syllables[len(syllables)-1]
+		last.phonemes = append(last.phonemes, cluster...)
+	} else if len(cluster) > 0 {
+		syllables = append(syllables, syllable{phonemes: cluster, stress: Unstressed})
+	}
+
+	return syllables
+}
+
+// onsetStart returns the index in cluster where the longest valid onset
+// begins.
+func onsetStart(cluster []string) int {
+	for start := range cluster {
+		onset := cluster[start:]
+
+		if len(onset) == 1 && onset[0] != "NG" || onsets[strings.Join(onset, " ")] {
+			return start
+		}
+	}
+
+	return len(cluster)
+}
+
+// ToIPA writes an ARPAbet pronunciation in the International Phonetic
+// Alphabet, marking primary stress with ˈ and secondary stress with ˌ
+// before the stressed syllable, e.g. "AH0 W EY1" becomes "əˈweɪ". Words
+// of one syllable carry no stress mark.
+func ToIPA(pron string) string {
+	syllables := syllabify(pron)
+
+	var ipa strings.Builder
+
+	for _, syl := range syllables {
+		if len(syllables) > 1 {
+			switch syl.stress {
+			case Primary:
+				ipa.WriteString("ˈ")
+			case Secondary:
+				ipa.WriteString("ˌ")
+			}
+		}
+
+		for _, phoneme := range syl.phonemes {
+			if reduced, ok := reducedIPA[phoneme]; ok && syl.stress == Unstressed && len(syllables) > 1 {
+				ipa.WriteString(reduced)
+
+				continue
+			}
+
+			ipa.WriteString(phones[phoneme].ipa)
+		}
+	}
+
+	return ipa.String()
+}
+
+// ToRespelling writes an ARPAbet pronunciation as a readable respelling
+// with hyphens between syllables and the syllable with primary stress in
+// capitals, e.g. "AH0 W EY1" becomes "uh-WAY".
+func ToRespelling(pron string) string {
+	syllables := syllabify(pron)
+	parts := make([]string, len(syllables))
+
+	for i, syl := range syllables {
+		var part strings.Builder
+
+		for j, phoneme := range syl.phonemes {
+			// A syllable that starts with the vowel AY is spelled "eye".
+			if phoneme == "AY" && j == 0 {
+				part.WriteString("eye")
+
+				continue
+			}
+
+			part.WriteString(phones[phoneme].respell)
+		}
+
+		parts[i] = part.String()
+		if syl.stress == Primary {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,92 @@
+package phonetics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Notation is a way of writing pronunciations.
+type Notation string
+
+// Notations supported by Convert.
+const (
+	NotationARPAbet Notation = "arpabet" // Datamuse's ARPAbet, e.g. "JH OY1" (default)
+	NotationIPA     Notation = "ipa"     // International Phonetic Alphabet, e.g. "dʒɔɪ"
+	NotationRespell Notation = "respell" // Readable respelling, e.g. "JOY"
+)
+
+// ErrInvalidNotation reports an unsupported pronunciation notation.
+var ErrInvalidNotation = errors.New("invalid pronunciation format")
+
+// notations lists the supported notations in the order shown in help
+// text.
+//
+//nolint:gochecknoglobals
+var notations = []Notation{NotationARPAbet, NotationIPA, NotationRespell}
+
+// NotationNames returns the supported notation names separated by "|".
+func NotationNames() string {
+	names := make([]string, len(notations))
+	for i, notation := range notations {
+		names[i] = string(notation)
+	}
+
+	return strings.Join(names, "|")
+}
+
+// ParseNotation converts a string such as "ipa" to a Notation.
+func ParseNotation(value string) (Notation, error) {
+	for _, notation := range notations {
+		if strings.EqualFold(value, string(notation)) {
+			return notation, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %q: expected one of %s", ErrInvalidNotation, value, NotationNames())
+}
+
+// String returns the notation name.
+func (n *Notation) String() string {
+	return string(*n)
+}
+
+// Set parses value into the notation, so that a Notation can be used as
+// a command-line flag.
+func (n *Notation) Set(value string) error {
+	notation, err := ParseNotation(value)
+	if err != nil {
+		return err
+	}
+
+	*n = notation
+
+	return nil
+}
+
+// Type returns the flag type name shown in help text.
+func (n *Notation) Type() string {
+	return "notation"
+}
+
+// Convert writes an ARPAbet pronunciation in the notation. Pronunciations
+// that are not ARPAbet, such as IPA requested from Datamuse with ipa=1,
+// and the ARPAbet notation itself are returned unchanged apart from
+// surrounding whitespace.
+func Convert(pron string, notation Notation) string {
+	pron = strings.TrimSpace(pron)
+
+	if !IsARPAbet(pron) {
+		return pron
+	}
+
+	switch notation {
+	case NotationIPA:
+		return ToIPA(pron)
+	case NotationRespell:
+		return ToRespelling(pron)
+	case "", NotationARPAbet:
+	}
+
+	return pron
+}
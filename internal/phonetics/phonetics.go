@@ -0,0 +1,147 @@
+// Package phonetics interprets the ARPAbet pronunciations returned by
+// Datamuse, such as "AH0 W EY1" for "away", to derive syllable stress
+// and meter.
+package phonetics
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Stress levels of a vowel in ARPAbet, written as a digit after it.
+const (
+	Unstressed = '0'
+	Primary    = '1'
+	Secondary  = '2'
+)
+
+// anySyllable in a StressPattern matches a syllable of any stress.
+const anySyllable = '?'
+
+// ErrInvalidStressPattern reports a stress pattern that cannot be parsed.
+var ErrInvalidStressPattern = errors.New("invalid stress pattern")
+
+// meters maps the names of metrical feet to their stress patterns.
+//
+//nolint:gochecknoglobals
+var meters = map[string]StressPattern{
+	"iamb":       "01",
+	"trochee":    "10",
+	"spondee":    "11",
+	"pyrrhic":    "00",
+	"anapest":    "001",
+	"dactyl":     "100",
+	"amphibrach": "010",
+}
+
+// Phonemes splits an ARPAbet pronunciation into its phonemes.
+func Phonemes(pron string) []string {
+	return strings.Fields(pron)
+}
+
+// Stress returns the stress of each syllable of an ARPAbet
+// pronunciation as a string of digits, e.g. "01" for "AH0 W EY1". Each
+// vowel carries a stress digit and forms one syllable.
+func Stress(pron string) string {
+	var stress strings.Builder
+
+	for _, phoneme := range Phonemes(pron) {
+		switch last := phoneme[len(phoneme)-1]; last {
+		case Unstressed, Primary, Secondary:
+			stress.WriteByte(last)
+		}
+	}
+
+	return stress.String()
+}
+
+// StressPattern is a sequence of syllable stresses to match against a
+// pronunciation: 0 is an unstressed syllable, 1 a stressed syllable
+// (primary or secondary stress), 2 a syllable with secondary stress and
+// ? any syllable. For example, "01" matches iambs such as "away".
+type StressPattern string
+
+// MeterNames returns the names of the metrical feet accepted by
+// ParseStressPattern, sorted.
+func MeterNames() []string {
+	names := make([]string, 0, len(meters))
+	for name := range meters {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// ParseStressPattern converts a pattern of 0, 1, 2 and ? characters, or
+// the name of a metrical foot such as "iamb", to a StressPattern.
+func ParseStressPattern(value string) (StressPattern, error) {
+	value = strings.TrimSpace(value)
+
+	if pattern, ok := meters[strings.ToLower(value)]; ok {
+		return pattern, nil
+	}
+
+	if value == "" || strings.Trim(value, "012?") != "" {
+		return "", fmt.Errorf("%w %q: expected 0, 1, 2 and ? characters or one of %s",
+			ErrInvalidStressPattern, value, strings.Join(MeterNames(), ", "))
+	}
+
+	return StressPattern(value), nil
+}
+
+// Match reports whether the ARPAbet pronunciation pron has the stress
+// pattern, syllable for syllable. An empty pattern matches every
+// pronunciation.
+func (p StressPattern) Match(pron string) bool {
+	if p == "" {
+		return true
+	}
+
+	stress := Stress(pron)
+	if len(stress) != len(p) {
+		return false
+	}
+
+	for i := range len(p) {
+		switch want, got := p[i], stress[i]; want {
+		case anySyllable:
+		case Primary:
+			if got == Unstressed {
+				return false
+			}
+		default:
+			if got != want {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// String returns the pattern.
+func (p *StressPattern) String() string {
+	return string(*p)
+}
+
+// Set parses value into the pattern, so that a StressPattern can be used
+// as a command-line flag.
+func (p *StressPattern) Set(value string) error {
+	pattern, err := ParseStressPattern(value)
+	if err != nil {
+		return err
+	}
+
+	*p = pattern
+
+	return nil
+}
+
+// Type returns the flag type name shown in help text.
+func (p *StressPattern) Type() string {
+	return "pattern"
+}
@@ -0,0 +1,118 @@
+package phonetics_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStress(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "01", phonetics.Stress("AH0 W EY1 "))
+	require.Equal(t, "1", phonetics.Stress("JH OY1 "))
+	require.Equal(t, "102", phonetics.Stress("D EY1 D R IY0 M IH2 NG"))
+	require.Empty(t, phonetics.Stress(""))
+}
+
+func TestParseStressPattern(t *testing.T) {
+	t.Parallel()
+
+	pattern, err := phonetics.ParseStressPattern("Iamb")
+	require.NoError(t, err)
+	require.Equal(t, phonetics.StressPattern("01"), pattern)
+
+	pattern, err = phonetics.ParseStressPattern(" 1?0 ")
+	require.NoError(t, err)
+	require.Equal(t, phonetics.StressPattern("1?0"), pattern)
+
+	for _, invalid := range []string{"", "0x1", "sonnet"} {
+		_, err = phonetics.ParseStressPattern(invalid)
+		require.ErrorIs(t, err, phonetics.ErrInvalidStressPattern, invalid)
+	}
+}
+
+func TestStressPattern_Match(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern  phonetics.StressPattern
+		pron     string
+		expected bool
+	}{
+		{"01", "AH0 W EY1 ", true},      // away
+		{"01", "T AH0 D EY1 ", true},    // today
+		{"01", "D EY1 T AY2 M ", false}, // daytime
+		{"11", "D EY1 T AY2 M ", true},  // secondary stress counts as stressed
+		{"12", "D EY1 T AY2 M ", true},
+		{"10", "D EY1 T AY2 M ", false},
+		{"1?", "D EY1 T AY2 M ", true},
+		{"1", "D EY1 ", true},
+		{"01", "D EY1 ", false},
+		{"01", "", false},
+		{"", "D EY1 ", true},
+	}
+
+	for _, test := range tests {
+		require.Equal(t, test.expected, test.pattern.Match(test.pron), "%s %s", test.pattern, test.pron)
+	}
+}
+
+func TestToIPA(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"AH0 W EY1 ":            "əˈweɪ",      // away
+		"JH OY1 ":               "dʒɔɪ",       // joy, no stress mark
+		"AE2 T L AE1 N T IH0 K": "ˌætˈlæntɪk", // atlantic
+		"M AH1 DH ER0":          "ˈmʌðɚ",      // mother, reduced ER
+		"K AH0 M Y UW1 T ER0":   "kəˈmjutɚ",   // computer, MY onset
+		"IH0 K S T R IY1 M":     "ɪkˈstɹim",   // extreme, STR onset
+		"S IH1 NG IH0 NG":       "ˈsɪŋɪŋ",     // singing, NG is no onset
+	}
+
+	for pron, expected := range tests {
+		require.Equal(t, expected, phonetics.ToIPA(pron), pron)
+	}
+}
+
+func TestToRespelling(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"AH0 W EY1 ":          "uh-WAY",
+		"JH OY1 ":             "JOY",
+		"AY1 S AH0 L EY2 T":   "EYE-suh-layt",
+		"B R AY1 N IY0":       "BRY-nee",
+		"K AH0 M Y UW1 T ER0": "kuh-MYOO-tur",
+	}
+
+	for pron, expected := range tests {
+		require.Equal(t, expected, phonetics.ToRespelling(pron), pron)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "JH OY1", phonetics.Convert("JH OY1 ", phonetics.NotationARPAbet))
+	require.Equal(t, "JH OY1", phonetics.Convert("JH OY1 ", ""))
+	require.Equal(t, "dʒɔɪ", phonetics.Convert("JH OY1 ", phonetics.NotationIPA))
+	require.Equal(t, "JOY", phonetics.Convert("JH OY1 ", phonetics.NotationRespell))
+
+	// Pronunciations that are already IPA are left alone.
+	require.Equal(t, "dʒˈɔɪ", phonetics.Convert("dʒˈɔɪ", phonetics.NotationRespell))
+	require.Empty(t, phonetics.Convert("", phonetics.NotationIPA))
+}
+
+func TestParseNotation(t *testing.T) {
+	t.Parallel()
+
+	notation, err := phonetics.ParseNotation("IPA")
+	require.NoError(t, err)
+	require.Equal(t, phonetics.NotationIPA, notation)
+
+	_, err = phonetics.ParseNotation("sampa")
+	require.ErrorIs(t, err, phonetics.ErrInvalidNotation)
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package quota
+
+import "os"
+
+// lockFile does nothing on systems without flock; concurrent processes
+// may then lose requests from the count.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile does nothing on systems without flock.
+func unlockFile(*os.File) error {
+	return nil
+}
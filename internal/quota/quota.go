@@ -0,0 +1,258 @@
+// Package quota tracks the number of Datamuse API requests made per day
+// in a file shared by every Polyhymnia process of the current user.
+package quota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// DefaultDailyLimit is the number of requests per day that Datamuse asks
+// clients to stay under.
+const DefaultDailyLimit = 100000
+
+// DefaultWarnRatio is the fraction of the daily limit at which a warning
+// is issued.
+const DefaultWarnRatio = 0.9
+
+// dateLayout formats the UTC day a counter belongs to.
+const dateLayout = "2006-01-02"
+
+// dirPerm and filePerm restrict the counter file to the current user.
+const (
+	dirPerm  = 0o700
+	filePerm = 0o600
+)
+
+// ErrQuotaExceeded is returned by Acquire in refuse mode once the daily
+// limit has been reached. It is the datamuseapi sentinel, so that the
+// client refuses the request rather than only warning about it.
+var ErrQuotaExceeded = datamuseapi.ErrQuotaExceeded //nolint:gochecknoglobals
+
+// Usage is the request count for one UTC day.
+type Usage struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	Limit int    `json:"-"`
+}
+
+// Remaining returns the number of requests left before the limit.
+func (u Usage) Remaining() int {
+	return max(u.Limit-u.Count, 0)
+}
+
+// Percent returns the share of the limit used, in percent.
+func (u Usage) Percent() float64 {
+	if u.Limit <= 0 {
+		return 0
+	}
+
+	return float64(u.Count) / float64(u.Limit) * 100 //nolint:mnd
+}
+
+// Options configures a Tracker.
+type Options struct {
+	// Path is the file holding the counter.
+	Path string
+	// Limit is the daily request limit.
+	Limit int
+	// WarnRatio is the fraction of Limit at which OnWarn is called.
+	WarnRatio float64
+	// Refuse makes Acquire fail once Limit has been reached instead of
+	// only warning.
+	Refuse bool
+	// OnWarn is called once per Tracker when usage first reaches the
+	// warning threshold.
+	OnWarn func(Usage)
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+}
+
+// Tracker counts requests in a file, starting a new count every UTC day.
+// The file is locked while it is updated, so that processes running at
+// the same time never lose each other's requests.
+type Tracker struct {
+	mu     sync.Mutex
+	opts   Options
+	warned bool
+}
+
+// DefaultPath returns the default counter file, "polyhymnia/quota.json"
+// under the user's state directory. The counter is kept out of the cache
+// directory, which may be cleared at any time. The state directory is
+// $XDG_STATE_HOME if set, ~/.local/state on Unix systems other than
+// macOS, and the user's configuration directory elsewhere.
+func DefaultPath() (string, error) {
+	stateDir, err := userStateDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate state directory: %w", err)
+	}
+
+	return filepath.Join(stateDir, "polyhymnia", "quota.json"), nil
+}
+
+// userStateDir returns the user's state directory as described by
+// DefaultPath.
+func userStateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+
+	switch runtime.GOOS {
+	case "darwin", "ios", "windows", "plan9":
+		return os.UserConfigDir() //nolint:wrapcheck
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return filepath.Join(home, ".local", "state"), nil
+}
+
+// New returns a Tracker configured with opts.
+func New(opts Options) *Tracker {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	return &Tracker{opts: opts}
+}
+
+// Usage returns today's request count.
+func (t *Tracker) Usage() (Usage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.load()
+}
+
+// Acquire records one request. In refuse mode it returns an error
+// wrapping ErrQuotaExceeded, without recording the request, once the
+// daily limit has been reached. Any other error reports a counter that
+// could not be read or written.
+func (t *Tracker) Acquire() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	unlock, err := t.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	usage, err := t.load()
+	if err != nil {
+		return err
+	}
+
+	if t.opts.Refuse && t.opts.Limit > 0 && usage.Count >= t.opts.Limit {
+		return fmt.Errorf("%w: %d of %d requests used on %s (UTC)",
+			ErrQuotaExceeded, usage.Count, usage.Limit, usage.Date)
+	}
+
+	usage.Count++
+
+	if err := t.save(usage); err != nil {
+		return err
+	}
+
+	threshold := t.opts.WarnRatio * float64(t.opts.Limit)
+	if !t.warned && t.opts.OnWarn != nil && t.opts.Limit > 0 && float64(usage.Count) >= threshold {
+		t.warned = true
+		t.opts.OnWarn(usage)
+	}
+
+	return nil
+}
+
+// load reads today's usage from the counter file. A missing file or a
+// counter from an earlier day yields a zero count.
+func (t *Tracker) load() (Usage, error) {
+	usage := Usage{Date: t.opts.Now().UTC().Format(dateLayout), Limit: t.opts.Limit}
+
+	data, err := os.ReadFile(t.opts.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return usage, nil
+	}
+
+	if err != nil {
+		return usage, fmt.Errorf("failed to read request counter: %w", err)
+	}
+
+	var stored Usage
+
+	// A corrupt counter is treated as empty rather than blocking queries.
+	if err := json.Unmarshal(data, &stored); err == nil && stored.Date == usage.Date {
+		usage.Count = stored.Count
+	}
+
+	return usage, nil
+}
+
+// lock takes an exclusive lock on the file next to the counter, waiting
+// for other processes to release it, and returns the function that
+// releases it.
+func (t *Tracker) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(t.opts.Path), dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create request counter directory: %w", err)
+	}
+
+	file, err := os.OpenFile(t.opts.Path+".lock", os.O_CREATE|os.O_RDWR, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock request counter: %w", err)
+	}
+
+	if err := lockFile(file); err != nil {
+		_ = file.Close()
+
+		return nil, fmt.Errorf("failed to lock request counter: %w", err)
+	}
+
+	return func() {
+		_ = unlockFile(file)
+		_ = file.Close()
+	}, nil
+}
+
+// save writes usage to the counter file.
+func (t *Tracker) save(usage Usage) error {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return fmt.Errorf("failed to encode request counter: %w", err)
+	}
+
+	// Write to a temporary file and rename it so that a concurrent
+	// reader never sees a partially written counter.
+	tmp, err := os.CreateTemp(filepath.Dir(t.opts.Path), "quota-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write request counter: %w", err)
+	}
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), t.opts.Path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to write request counter: %w", err)
+	}
+
+	return nil
+}
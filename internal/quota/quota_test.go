@@ -0,0 +1,155 @@
+package quota_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/quota"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedClock returns a Now function that reports *now.
+func fixedClock(now *time.Time) func() time.Time {
+	return func() time.Time { return *now }
+}
+
+func TestTracker_CountsAndPersists(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "quota.json")
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tracker := quota.New(quota.Options{Path: path, Limit: 100, Now: fixedClock(&now)})
+	for range 3 {
+		require.NoError(t, tracker.Acquire())
+	}
+
+	usage, err := quota.New(quota.Options{Path: path, Limit: 100, Now: fixedClock(&now)}).Usage()
+	require.NoError(t, err)
+	require.Equal(t, quota.Usage{Date: "2026-10-18", Count: 3, Limit: 100}, usage)
+	require.Equal(t, 97, usage.Remaining())
+}
+
+func TestTracker_ResetsEachDay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "quota.json")
+	now := time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)
+
+	tracker := quota.New(quota.Options{Path: path, Limit: 100, Now: fixedClock(&now)})
+	require.NoError(t, tracker.Acquire())
+
+	now = now.Add(2 * time.Minute)
+
+	usage, err := tracker.Usage()
+	require.NoError(t, err)
+	require.Equal(t, "2026-10-19", usage.Date)
+	require.Zero(t, usage.Count)
+}
+
+func TestTracker_WarnsOnceAtThreshold(t *testing.T) {
+	t.Parallel()
+
+	var warnings []quota.Usage
+
+	tracker := quota.New(quota.Options{
+		Path:      filepath.Join(t.TempDir(), "quota.json"),
+		Limit:     4,
+		WarnRatio: 0.5,
+		OnWarn:    func(u quota.Usage) { warnings = append(warnings, u) },
+	})
+
+	for range 4 {
+		require.NoError(t, tracker.Acquire())
+	}
+
+	require.Len(t, warnings, 1)
+	require.Equal(t, 2, warnings[0].Count)
+}
+
+func TestTracker_RefusesAtLimit(t *testing.T) {
+	t.Parallel()
+
+	tracker := quota.New(quota.Options{
+		Path:   filepath.Join(t.TempDir(), "quota.json"),
+		Limit:  2,
+		Refuse: true,
+	})
+
+	require.NoError(t, tracker.Acquire())
+	require.NoError(t, tracker.Acquire())
+	require.ErrorIs(t, tracker.Acquire(), quota.ErrQuotaExceeded)
+
+	usage, err := tracker.Usage()
+	require.NoError(t, err)
+	require.Equal(t, 2, usage.Count)
+}
+
+// TestTracker_ConcurrentTrackers verifies that trackers sharing a file,
+// as separate processes do, never lose each other's requests.
+func TestTracker_ConcurrentTrackers(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "quota.json")
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, 200)
+
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			tracker := quota.New(quota.Options{Path: path, Limit: 1000})
+			for range 25 {
+				errs <- tracker.Acquire()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	usage, err := quota.New(quota.Options{Path: path, Limit: 1000}).Usage()
+	require.NoError(t, err)
+	require.Equal(t, 200, usage.Count)
+
+	leftovers, err := filepath.Glob(filepath.Join(filepath.Dir(path), "*.tmp"))
+	require.NoError(t, err)
+	require.Empty(t, leftovers)
+}
+
+func TestTracker_UnwritableCounter(t *testing.T) {
+	t.Parallel()
+
+	// A regular file where the directory should be makes the counter
+	// impossible to write.
+	parent := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(parent, nil, 0o600))
+
+	err := quota.New(quota.Options{Path: filepath.Join(parent, "quota.json"), Limit: 1, Refuse: true}).Acquire()
+
+	require.Error(t, err)
+	require.NotErrorIs(t, err, quota.ErrQuotaExceeded)
+}
+
+// TestDefaultPath tests that the counter is kept in the state directory.
+//
+//nolint:paralleltest // The state directory is chosen by t.Setenv.
+func TestDefaultPath(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	path, err := quota.DefaultPath()
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(stateDir, "polyhymnia", "quota.json"), path)
+}
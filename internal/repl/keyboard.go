@@ -0,0 +1,120 @@
+package repl
+
+import (
+	"bytes"
+	"context"
+	"io"
+)
+
+// Keys that cancel a running query at a terminal in raw mode, where
+// Ctrl-C does not raise an interrupt.
+const (
+	keyCtrlC  = '\x03'
+	keyEscape = '\x1b'
+)
+
+// keyboardBufferSize is the size of each read from the terminal.
+const keyboardBufferSize = 256
+
+// Interrupter runs the lines of a session so that they can be canceled
+// while they run. *Keyboard implements it.
+type Interrupter interface {
+	// Interruptible runs fn with a context derived from ctx that is
+	// canceled when the user interrupts it, and returns the error of fn.
+	Interruptible(ctx context.Context, fn func(context.Context) error) error
+}
+
+// Keyboard reads the input of a terminal in raw mode in the background.
+// It is the input of the line editor, and while a line runs it watches
+// the keys for Ctrl-C and Esc, which cancel it. Other keys typed while a
+// line runs are kept for the line editor.
+type Keyboard struct {
+	chunks  chan []byte
+	pending []byte
+	err     error // The read error, set before chunks is closed.
+}
+
+// NewKeyboard returns a Keyboard reading from in until the end of the
+// input or a read error.
+func NewKeyboard(in io.Reader) *Keyboard {
+	keyboard := &Keyboard{chunks: make(chan []byte)}
+
+	go keyboard.read(in)
+
+	return keyboard
+}
+
+// read sends each chunk read from in to the keyboard's channel, closing
+// it when reading fails.
+func (k *Keyboard) read(in io.Reader) {
+	buf := make([]byte, keyboardBufferSize)
+
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			k.chunks <- bytes.Clone(buf[:n])
+		}
+
+		if err != nil {
+			k.err = err
+			close(k.chunks)
+
+			return
+		}
+	}
+}
+
+// Read returns the keys typed since the last read, waiting for a key if
+// there are none. It is not safe to call while Interruptible runs.
+func (k *Keyboard) Read(p []byte) (int, error) {
+	if len(k.pending) == 0 {
+		chunk, ok := <-k.chunks
+		if !ok {
+			return 0, k.err
+		}
+
+		k.pending = chunk
+	}
+
+	n := copy(p, k.pending)
+	k.pending = k.pending[n:]
+
+	return n, nil
+}
+
+// Interruptible runs fn with a context that is canceled when Ctrl-C or
+// Esc is typed, and returns the error of fn.
+func (k *Keyboard) Interruptible(ctx context.Context, fn func(context.Context) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() { done <- fn(ctx) }()
+
+	chunks := k.chunks
+
+	for {
+		select {
+		case err := <-done:
+			return err
+		case chunk, ok := <-chunks:
+			switch {
+			case !ok:
+				// Read reports the end of the input once fn returns.
+				chunks = nil
+			case interrupts(chunk):
+				cancel()
+			default:
+				k.pending = append(k.pending, chunk...)
+			}
+		}
+	}
+}
+
+// interrupts reports whether a chunk of input holds Ctrl-C or is an
+// Escape key on its own rather than the start of an escape sequence,
+// such as that of an arrow key.
+func interrupts(chunk []byte) bool {
+	return bytes.IndexByte(chunk, keyCtrlC) >= 0 || (len(chunk) == 1 && chunk[0] == keyEscape)
+}
@@ -0,0 +1,427 @@
+// Package repl implements Polyhymnia's interactive mode, in which each
+// line is a query or a command that changes the settings shared by the
+// queries of the session.
+package repl
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultexpr"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
+)
+
+// ErrQuit is returned by Execute for the ":quit" command.
+var ErrQuit = errors.New("quit")
+
+// ErrUnknownCommand reports a line that does not start with a known
+// query or command.
+var ErrUnknownCommand = errors.New("unknown command")
+
+// ErrUsage reports a query or command with missing or extra arguments.
+var ErrUsage = errors.New("usage")
+
+// ErrNoResultSet reports a command that refines the previous result set
+// when there is none.
+var ErrNoResultSet = errors.New("no previous results")
+
+// Querier runs Datamuse queries. *datamuseapi.Client satisfies it.
+type Querier interface {
+	QueryContext(ctx context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error)
+	SuggestContext(ctx context.Context, params datamuseapi.SuggestParams) ([]datamuseapi.APIResponse, error)
+}
+
+// LineReader reads the lines typed in a session. *term.Terminal
+// satisfies it.
+type LineReader interface {
+	ReadLine() (string, error)
+}
+
+// scannerReader is a LineReader for input that is not a terminal.
+type scannerReader struct {
+	scanner *bufio.Scanner
+}
+
+// NewLineReader returns a LineReader that reads lines from r without
+// line editing, for input that is not a terminal.
+func NewLineReader(r io.Reader) LineReader {
+	return &scannerReader{scanner: bufio.NewScanner(r)}
+}
+
+// ReadLine returns the next line, or io.EOF at the end of the input.
+func (r *scannerReader) ReadLine() (string, error) {
+	if r.scanner.Scan() {
+		return r.scanner.Text(), nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return "", io.EOF
+}
+
+// Session runs the queries and commands of an interactive session. The
+// settings persist from one query to the next, and the results of the
+// last query remain available to ":filter" and ":sort".
+type Session struct {
+	client   Querier
+	out      io.Writer
+	errOut   io.Writer
+	settings Settings
+	initial  Settings
+	results  []datamuseapi.APIResponse
+	// interrupter, if not nil, runs each line so that it can be canceled.
+	interrupter Interrupter
+}
+
+// NewSession returns a Session that runs queries with client, starting
+// from settings, and writes results to out and notices to errOut.
+func NewSession(client Querier, out, errOut io.Writer, settings Settings) *Session {
+	return &Session{client: client, out: out, errOut: errOut, settings: settings, initial: settings}
+}
+
+// SetInterrupter makes Run execute each line with interrupter, so that a
+// query can be canceled while it runs without ending the session.
+func (s *Session) SetInterrupter(interrupter Interrupter) {
+	s.interrupter = interrupter
+}
+
+// Settings returns the current settings.
+func (s *Session) Settings() Settings {
+	return s.settings
+}
+
+// Results returns the results of the last query or refinement.
+func (s *Session) Results() []datamuseapi.APIResponse {
+	return s.results
+}
+
+// Run executes each line read from reader until the end of the input or
+// ":quit". Errors are written to the session's error output and do not
+// end the session, unless ctx is canceled. A line canceled through the
+// session's interrupter only ends that line.
+func (s *Session) Run(ctx context.Context, reader LineReader) error {
+	for {
+		line, err := reader.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if s.interrupter != nil {
+			err = s.interrupter.Interruptible(ctx, func(ctx context.Context) error {
+				return s.Execute(ctx, line)
+			})
+		} else {
+			err = s.Execute(ctx, line)
+		}
+
+		switch {
+		case errors.Is(err, ErrQuit):
+			return nil
+		case ctx.Err() != nil:
+			return ctx.Err() //nolint:wrapcheck
+		case errors.Is(err, context.Canceled):
+			fmt.Fprintln(s.errOut, "Query canceled.")
+		case err != nil:
+			fmt.Fprintf(s.errOut, "Error: %v\n", err)
+		}
+	}
+}
+
+// Execute runs one line: a query such as "ml ocean max=5", or a command
+// starting with ":" such as ":set max 20". Blank lines and lines
+// starting with "#" are ignored.
+func (s *Session) Execute(ctx context.Context, line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+
+	name, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+
+	if strings.HasPrefix(name, ":") {
+		return s.command(name, rest)
+	}
+
+	return s.query(ctx, strings.ToLower(name), strings.Fields(rest))
+}
+
+// query runs a query command. Arguments of the form key=value change a
+// setting for this query only; the others make up the term.
+func (s *Session) query(ctx context.Context, name string, args []string) error {
+	settings := s.settings
+
+	var terms []string
+
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, "=")
+		if !found {
+			terms = append(terms, arg)
+
+			continue
+		}
+
+		if err := settings.Set(key, value); err != nil {
+			return err
+		}
+	}
+
+	params := settings.Params
+
+	switch name {
+	case "ml":
+		params.Ml = strings.Join(terms, " ")
+	case "sl":
+		params.Sl = strings.Join(terms, " ")
+	case "sp":
+		params.Sp = strings.Join(terms, " ")
+	case "sug":
+		return s.suggest(ctx, settings, strings.Join(terms, " "))
+	case "def":
+		return s.define(ctx, settings, strings.Join(terms, " "))
+	case "rel":
+		if len(terms) < 1 {
+			return fmt.Errorf("%w: rel <code> <term>", ErrUsage)
+		}
+
+		name, terms = terms[0], terms[1:]
+
+		fallthrough
+	default:
+		code, err := datamuseapi.ParseRelationCode(name)
+		if err != nil {
+			return fmt.Errorf("%w %q (type :help for help)", ErrUnknownCommand, name)
+		}
+
+		params.Rel = []datamuseapi.RelatedWord{{Code: code, Term: strings.Join(terms, " ")}}
+	}
+
+	if len(terms) < 1 {
+		return fmt.Errorf("%w: %s <term> [setting=value ...]", ErrUsage, name)
+	}
+
+	return s.queryWords(ctx, settings, params)
+}
+
+// queryWords runs a word query and shows the filtered and sorted
+// results. When a filter or sort keys are set, the query asks for as
+// many results as the API allows so that enough remain after filtering
+// and the first by the sort keys are kept.
+func (s *Session) queryWords(ctx context.Context, settings Settings, params datamuseapi.QueryParams) error {
+	params.Md = settings.metadata()
+	limit := params.Max
+
+	if settings.Filter.Active() || len(settings.Sort) > 0 {
+		params.Max = datamuseapi.MaxResults
+	}
+
+	results, err := s.client.QueryContext(ctx, params)
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	results = settings.Filter.Apply(results)
+	resultsort.Sort(results, settings.Sort)
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return s.show(settings.Options, results)
+}
+
+// suggest shows the words that complete prefix.
+func (s *Session) suggest(ctx context.Context, settings Settings, prefix string) error {
+	if prefix == "" {
+		return fmt.Errorf("%w: sug <prefix> [setting=value ...]", ErrUsage)
+	}
+
+	results, err := s.client.SuggestContext(ctx, datamuseapi.SuggestParams{
+		S: prefix, Max: settings.Params.Max, V: settings.Params.V,
+	})
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	resultsort.Sort(results, settings.Sort)
+
+	return s.show(settings.Options, results)
+}
+
+// define shows the definitions of word. A word without definitions
+// counts as no results.
+func (s *Session) define(ctx context.Context, settings Settings, word string) error {
+	if word == "" {
+		return fmt.Errorf("%w: def <word> [setting=value ...]", ErrUsage)
+	}
+
+	settings.Options.ShowDefinitions = true
+
+	results, err := s.client.QueryContext(ctx, datamuseapi.QueryParams{
+		Sp: word, Md: settings.Options.ToMetadataString(""), V: settings.Params.V, Max: 1,
+	})
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	if len(results) > 1 {
+		results = results[:1]
+	}
+
+	if len(results) > 0 && len(results[0].Definitions) == 0 {
+		results = nil
+	}
+
+	return s.show(settings.Options, results)
+}
+
+// show prints results and keeps them for refinement.
+func (s *Session) show(options resultprinter.DisplayOptions, results []datamuseapi.APIResponse) error {
+	s.results = results
+
+	return resultprinter.NewPrinter(s.out, s.errOut, options).Print(results) //nolint:wrapcheck
+}
+
+// command runs a command starting with ":".
+func (s *Session) command(name, args string) error {
+	switch strings.ToLower(name) {
+	case ":q", ":quit", ":exit":
+		return ErrQuit
+	case ":h", ":help":
+		s.printHelp()
+	case ":set":
+		return s.set(args)
+	case ":reset":
+		s.settings = s.initial
+	case ":filter":
+		return s.filter(args)
+	case ":sort":
+		return s.sort(args)
+	case ":results":
+		if s.results == nil {
+			return ErrNoResultSet
+		}
+
+		return s.show(s.settings.Options, s.results)
+	default:
+		return fmt.Errorf("%w %q (type :help for help)", ErrUnknownCommand, name)
+	}
+
+	return nil
+}
+
+// set lists the settings, shows one or changes one.
+func (s *Session) set(args string) error {
+	name, value, found := strings.Cut(args, " ")
+
+	switch {
+	case args == "":
+		for _, setting := range settings {
+			line := fmt.Sprintf("%-8s %s", setting.name, setting.get(&s.settings))
+			fmt.Fprintln(s.out, strings.TrimRight(line, " "))
+		}
+	case !found:
+		value, err := s.settings.Get(name)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(s.out, "%s %s\n", name, value)
+	default:
+		return s.settings.Set(name, strings.TrimSpace(value))
+	}
+
+	return nil
+}
+
+// filter keeps the previous results that match a condition written in
+// the --where expression language.
+func (s *Session) filter(args string) error {
+	if s.results == nil {
+		return ErrNoResultSet
+	}
+
+	if args == "" {
+		return fmt.Errorf("%w: :filter <expression>", ErrUsage)
+	}
+
+	condition, err := resultexpr.ParseCondition(args)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	results, err := resultexpr.Filter(s.results, condition)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return s.show(s.settings.Options, results)
+}
+
+// sort orders the previous results by comma- or space-separated keys.
+func (s *Session) sort(args string) error {
+	if s.results == nil {
+		return ErrNoResultSet
+	}
+
+	keys, err := resultsort.Parse(strings.Fields(strings.ReplaceAll(args, ",", " ")))
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if len(keys) < 1 {
+		return fmt.Errorf("%w: :sort <key>[,<key>...]", ErrUsage)
+	}
+
+	results := slices.Clone(s.results)
+	resultsort.Sort(results, keys)
+
+	return s.show(s.settings.Options, results)
+}
+
+// Commands returns the names of the queries and commands, for
+// completion.
+func Commands() []string {
+	return []string{
+		"ml", "sl", "sp", "rel", "sug", "def", "rhy", "syn", "ant",
+		":set", ":reset", ":filter", ":sort", ":results", ":help", ":quit",
+	}
+}
+
+// printHelp describes the queries, commands and settings.
+func (s *Session) printHelp() {
+	fmt.Fprint(s.out, `Queries (setting=value arguments apply to one query, e.g. "rhy day syl=2"):
+  ml <term>          Words with a meaning similar to the term
+  sl <term>          Words that sound like the term
+  sp <pattern>       Words spelled like the pattern (* and ? are wildcards)
+  rel <code> <term>  Words related to the term; the code alone also works, e.g. "syn joy"
+  sug <prefix>       Words that complete the prefix
+  def <word>         Definitions of the word
+
+Commands:
+  :set [name [value]]  List, show or change the settings
+  :reset               Restore the settings the session started with
+  :filter <expr>       Keep previous results matching a --where expression
+  :sort <keys>         Sort previous results, e.g. -freq,alpha
+  :results             Show the previous results again
+  :help                Show this help
+  :quit                Leave (also Ctrl-D)
+
+Settings:
+`)
+
+	for _, setting := range settings {
+		fmt.Fprintf(s.out, "  %-8s %s\n", setting.name, setting.help)
+	}
+}
@@ -0,0 +1,149 @@
+package repl_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/repl"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQuerier records the queries it receives and answers each with the
+// same results.
+type fakeQuerier struct {
+	params  []datamuseapi.QueryParams
+	suggest []datamuseapi.SuggestParams
+	results []datamuseapi.APIResponse
+}
+
+func (f *fakeQuerier) QueryContext(_ context.Context, params datamuseapi.QueryParams) (
+	[]datamuseapi.APIResponse, error,
+) {
+	f.params = append(f.params, params)
+
+	return append([]datamuseapi.APIResponse{}, f.results...), nil
+}
+
+func (f *fakeQuerier) SuggestContext(_ context.Context, params datamuseapi.SuggestParams) (
+	[]datamuseapi.APIResponse, error,
+) {
+	f.suggest = append(f.suggest, params)
+
+	return append([]datamuseapi.APIResponse{}, f.results...), nil
+}
+
+// newTestSession returns a session with a fake querier answering with
+// three rhymes of "day".
+func newTestSession() (*repl.Session, *fakeQuerier, *bytes.Buffer) {
+	querier := &fakeQuerier{results: []datamuseapi.APIResponse{
+		{Word: "way", Score: 300, NumSyllables: 1, Frequency: 900},
+		{Word: "away", Score: 200, NumSyllables: 2, Frequency: 400},
+		{Word: "today", Score: 100, NumSyllables: 2, Frequency: 600},
+	}}
+
+	var out bytes.Buffer
+
+	settings := repl.Settings{Params: datamuseapi.QueryParams{Max: 100}}
+
+	return repl.NewSession(querier, &out, &out, settings), querier, &out
+}
+
+func TestSession_Query(t *testing.T) {
+	t.Parallel()
+
+	session, querier, out := newTestSession()
+	ctx := context.Background()
+
+	require.NoError(t, session.Execute(ctx, "ml deep blue sea"))
+	require.Equal(t, "deep blue sea", querier.params[0].Ml)
+	require.Equal(t, 100, querier.params[0].Max)
+	require.Equal(t, "way\n\naway\n\ntoday\n\n", out.String())
+
+	// A relation code is a query of its own; inline settings apply to
+	// this query only and filters fetch extra results.
+	out.Reset()
+	require.NoError(t, session.Execute(ctx, "rhy day syl=2"))
+	require.Equal(t, []datamuseapi.RelatedWord{{Code: datamuseapi.RelRhyme, Term: "day"}}, querier.params[1].Rel)
+	require.Equal(t, datamuseapi.MaxResults, querier.params[1].Max)
+	require.Equal(t, "s", querier.params[1].Md)
+	require.Equal(t, "away\n\ntoday\n\n", out.String())
+
+	settings := session.Settings()
+	syllables, err := settings.Get("syl")
+	require.NoError(t, err)
+	require.Empty(t, syllables)
+
+	require.NoError(t, session.Execute(ctx, "rel ant joy"))
+	require.Equal(t, datamuseapi.RelAntonym, querier.params[2].Rel[0].Code)
+}
+
+func TestSession_Set(t *testing.T) {
+	t.Parallel()
+
+	session, querier, out := newTestSession()
+	ctx := context.Background()
+
+	require.NoError(t, session.Execute(ctx, ":set max 20"))
+	require.NoError(t, session.Execute(ctx, ":set md dfp"))
+	require.NoError(t, session.Execute(ctx, "sl jirraf"))
+	require.Equal(t, 20, querier.params[0].Max)
+	require.Equal(t, "dfp", querier.params[0].Md)
+
+	out.Reset()
+	require.NoError(t, session.Execute(ctx, ":set max"))
+	require.Equal(t, "max 20\n", out.String())
+
+	require.ErrorIs(t, session.Execute(ctx, ":set max lots"), repl.ErrInvalidSetting)
+	require.ErrorIs(t, session.Execute(ctx, ":set colour red"), repl.ErrUnknownSetting)
+
+	require.NoError(t, session.Execute(ctx, ":reset"))
+	require.Equal(t, 100, session.Settings().Params.Max)
+}
+
+func TestSession_Refine(t *testing.T) {
+	t.Parallel()
+
+	session, _, out := newTestSession()
+	ctx := context.Background()
+
+	require.ErrorIs(t, session.Execute(ctx, ":sort alpha"), repl.ErrNoResultSet)
+	require.NoError(t, session.Execute(ctx, "rhy day"))
+
+	out.Reset()
+	require.NoError(t, session.Execute(ctx, ":filter syllables == 2"))
+	require.Equal(t, "away\n\ntoday\n\n", out.String())
+
+	out.Reset()
+	require.NoError(t, session.Execute(ctx, ":sort -freq"))
+	require.Equal(t, "today\n\naway\n\n", out.String())
+	require.Len(t, session.Results(), 2)
+}
+
+// TestSession_SortBeforeLimit tests that sorting fetches extra results
+// and keeps the first by the sort keys.
+func TestSession_SortBeforeLimit(t *testing.T) {
+	t.Parallel()
+
+	session, querier, out := newTestSession()
+	ctx := context.Background()
+
+	require.NoError(t, session.Execute(ctx, "rhy day max=2 sort=alpha"))
+	require.Equal(t, datamuseapi.MaxResults, querier.params[0].Max)
+	require.Equal(t, "away\n\ntoday\n\n", out.String())
+}
+
+func TestSession_Run(t *testing.T) {
+	t.Parallel()
+
+	session, querier, out := newTestSession()
+	input := "# comment\n\nsug wa\nbogus\n:quit\nml never run\n"
+
+	require.NoError(t, session.Run(context.Background(), repl.NewLineReader(strings.NewReader(input))))
+	require.Len(t, querier.suggest, 1)
+	require.Equal(t, "wa", querier.suggest[0].S)
+	require.Empty(t, querier.params)
+	require.Contains(t, out.String(), `Error: unknown command "bogus"`)
+}
@@ -0,0 +1,313 @@
+package repl
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultfilter"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
+)
+
+// ErrUnknownSetting reports a setting name that does not exist.
+var ErrUnknownSetting = errors.New("unknown setting")
+
+// ErrInvalidSetting reports a value that a setting does not accept.
+var ErrInvalidSetting = errors.New("invalid setting")
+
+// Settings are the query parameters, display options, filter and sort
+// order that apply to the queries of a session.
+type Settings struct {
+	Params  datamuseapi.QueryParams
+	Options resultprinter.DisplayOptions
+	Filter  resultfilter.Filter
+	Sort    []resultsort.Key
+}
+
+// setting is a named value that ":set" and inline key=value options
+// change.
+type setting struct {
+	name string
+	help string
+	get  func(s *Settings) string
+	set  func(s *Settings, value string) error
+}
+
+// metadataOptions maps the Datamuse metadata letters to the display
+// options they enable.
+func metadataOptions(options *resultprinter.DisplayOptions) map[rune]*bool {
+	return map[rune]*bool{
+		'd': &options.ShowDefinitions,
+		'f': &options.ShowFrequency,
+		'p': &options.ShowPOS,
+		'r': &options.ShowPronunciation,
+		's': &options.ShowSyllables,
+	}
+}
+
+// settings lists the settings in the order shown by ":set" and ":help".
+//
+//nolint:gochecknoglobals
+var settings = []setting{
+	{
+		name: "max", help: "Maximum number of results (1-1000)",
+		get: func(s *Settings) string { return strconv.Itoa(s.Params.Max) },
+		set: func(s *Settings, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > datamuseapi.MaxResults {
+				return fmt.Errorf("%w max %q: expected a number from 1 to %d", ErrInvalidSetting, value,
+					datamuseapi.MaxResults)
+			}
+
+			s.Params.Max = n
+
+			return nil
+		},
+	},
+	{
+		name: "md", help: "Metadata to show: any of dfprs, or none",
+		get: func(s *Settings) string {
+			var letters strings.Builder
+
+			for _, letter := range "dfprs" {
+				if *metadataOptions(&s.Options)[letter] {
+					letters.WriteRune(letter)
+				}
+			}
+
+			return letters.String()
+		},
+		set: func(s *Settings, value string) error {
+			if strings.EqualFold(value, "none") {
+				value = ""
+			}
+
+			options := s.Options
+			flags := metadataOptions(&options)
+
+			for _, flag := range flags {
+				*flag = false
+			}
+
+			for _, letter := range strings.ToLower(value) {
+				flag, ok := flags[letter]
+				if !ok {
+					return fmt.Errorf("%w md %q: expected any of dfprs, or none", ErrInvalidSetting, value)
+				}
+
+				*flag = true
+			}
+
+			s.Options = options
+
+			return nil
+		},
+	},
+	stringSetting("v", "Vocabulary identifier, e.g. es", func(s *Settings) *string { return &s.Params.V }),
+	stringSetting("lc", "Left context", func(s *Settings) *string { return &s.Params.Lc }),
+	stringSetting("rc", "Right context", func(s *Settings) *string { return &s.Params.Rc }),
+	{
+		name: "topics", help: "Topic words, comma-separated",
+		get: func(s *Settings) string { return strings.Join(s.Params.Topics, ",") },
+		set: func(s *Settings, value string) error {
+			s.Params.Topics = splitList(value)
+
+			return nil
+		},
+	},
+	boolSetting("score", "Show scores (on or off)", func(s *Settings) *bool { return &s.Options.ShowScore }),
+	boolSetting("count", "Show the number of results (on or off)",
+		func(s *Settings) *bool { return &s.Options.ShowCountFlag }),
+	boolSetting("url", "Show the query URL (on or off)", func(s *Settings) *bool { return &s.Options.ShowQueryURL }),
+	{
+		name: "output", help: "Output format (" + resultprinter.FormatNames() + ")",
+		get: func(s *Settings) string { return s.Options.Format.String() },
+		set: func(s *Settings, value string) error { return s.Options.Format.Set(value) },
+	},
+	{
+		name: "pron", help: "Pronunciation notation (arpabet|ipa|respell)",
+		get: func(s *Settings) string { return s.Options.PronNotation.String() },
+		set: func(s *Settings, value string) error { return s.Options.PronNotation.Set(value) },
+	},
+	{
+		name: "sort", help: "Sort keys, comma-separated (" + resultsort.FieldNames() + ", prefix - for descending)",
+		get: func(s *Settings) string {
+			keys := make([]string, len(s.Sort))
+			for i, key := range s.Sort {
+				keys[i] = key.String()
+			}
+
+			return strings.Join(keys, ",")
+		},
+		set: func(s *Settings, value string) error {
+			keys, err := resultsort.Parse(splitList(value))
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			s.Sort = keys
+
+			return nil
+		},
+	},
+	rangeSetting("syl", "Keep words with this many syllables, e.g. 2 or 2-3",
+		func(s *Settings) *resultfilter.Range { return &s.Filter.Syllables }),
+	rangeSetting("len", "Keep words with this many characters, e.g. 4-7",
+		func(s *Settings) *resultfilter.Range { return &s.Filter.Length }),
+	{
+		name: "pos", help: "Keep words with any of these parts of speech, comma-separated",
+		get: func(s *Settings) string { return strings.Join(s.Filter.POS, ",") },
+		set: func(s *Settings, value string) error {
+			s.Filter.POS = splitList(value)
+
+			return nil
+		},
+	},
+	{
+		name: "freq", help: "Keep words at least this frequent (per million words)",
+		get: func(s *Settings) string { return strconv.FormatFloat(s.Filter.MinFreq, 'g', -1, 64) },
+		set: func(s *Settings, value string) error {
+			freq, err := strconv.ParseFloat(value, 64)
+			if err != nil || freq < 0 {
+				return fmt.Errorf("%w freq %q: expected a non-negative number", ErrInvalidSetting, value)
+			}
+
+			s.Filter.MinFreq = freq
+
+			return nil
+		},
+	},
+	{
+		name: "stress", help: "Keep words with this stress pattern or meter, e.g. 01 or iamb",
+		get: func(s *Settings) string { return s.Filter.Stress.String() },
+		set: func(s *Settings, value string) error {
+			if value == "" {
+				s.Filter.Stress = ""
+
+				return nil
+			}
+
+			return s.Filter.Stress.Set(value)
+		},
+	},
+	boolSetting("single", "Drop multiword expressions (on or off)",
+		func(s *Settings) *bool { return &s.Filter.SingleWord }),
+}
+
+// stringSetting returns a setting that stores its value as is.
+func stringSetting(name, help string, field func(s *Settings) *string) setting {
+	return setting{
+		name: name, help: help,
+		get: func(s *Settings) string { return *field(s) },
+		set: func(s *Settings, value string) error {
+			*field(s) = value
+
+			return nil
+		},
+	}
+}
+
+// boolSetting returns a setting that is turned on or off.
+func boolSetting(name, help string, field func(s *Settings) *bool) setting {
+	return setting{
+		name: name, help: help,
+		get: func(s *Settings) string {
+			if *field(s) {
+				return "on"
+			}
+
+			return "off"
+		},
+		set: func(s *Settings, value string) error {
+			switch strings.ToLower(value) {
+			case "on", "true", "yes", "1":
+				*field(s) = true
+			case "off", "false", "no", "0":
+				*field(s) = false
+			default:
+				return fmt.Errorf("%w %s %q: expected on or off", ErrInvalidSetting, name, value)
+			}
+
+			return nil
+		},
+	}
+}
+
+// rangeSetting returns a setting holding a range; an empty value clears
+// it.
+func rangeSetting(name, help string, field func(s *Settings) *resultfilter.Range) setting {
+	return setting{
+		name: name, help: help,
+		get: func(s *Settings) string { return field(s).String() },
+		set: func(s *Settings, value string) error {
+			if value == "" {
+				*field(s) = resultfilter.Range{}
+
+				return nil
+			}
+
+			return field(s).Set(value)
+		},
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// lookupSetting returns the setting with the given name.
+func lookupSetting(name string) (setting, error) {
+	for _, s := range settings {
+		if s.name == name {
+			return s, nil
+		}
+	}
+
+	return setting{}, fmt.Errorf("%w %q (type :set to list the settings)", ErrUnknownSetting, name)
+}
+
+// Set changes the named setting to value, as ":set name value" does.
+func (s *Settings) Set(name, value string) error {
+	setting, err := lookupSetting(strings.ToLower(name))
+	if err != nil {
+		return err
+	}
+
+	return setting.set(s, value)
+}
+
+// Get returns the value of the named setting.
+func (s *Settings) Get(name string) (string, error) {
+	setting, err := lookupSetting(strings.ToLower(name))
+	if err != nil {
+		return "", err
+	}
+
+	return setting.get(s), nil
+}
+
+// metadata returns the Datamuse metadata letters for a query: those of
+// the display options and those needed to filter and sort the results.
+func (s *Settings) metadata() string {
+	var md strings.Builder
+
+	for _, letter := range s.Options.ToMetadataString("") + s.Filter.Metadata() + resultsort.Metadata(s.Sort) {
+		if !strings.ContainsRune(md.String(), letter) {
+			md.WriteRune(letter)
+		}
+	}
+
+	return md.String()
+}
@@ -0,0 +1,288 @@
+// Package respcache provides a persistent on-disk cache for Datamuse API
+// response bodies with a time-to-live and a total size limit.
+package respcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTTL is the default time a cached response stays fresh.
+const DefaultTTL = 24 * time.Hour
+
+// DefaultMaxBytes is the default limit on the total size of the cache,
+// set to fifty mebibytes.
+const DefaultMaxBytes = 50 << 20
+
+// entrySuffix is the file name suffix of cache entries.
+const entrySuffix = ".json"
+
+// tempPrefix is the file name prefix of entries being written.
+const tempPrefix = "tmp-"
+
+// staleTempAge is the age after which a temporary file is taken to be
+// left behind by a process that stopped while writing an entry.
+const staleTempAge = time.Minute
+
+// pruneRatio is the fraction of MaxBytes that pruning brings the cache
+// down to, so that a full cache is not scanned again on every write.
+const pruneRatio = 0.9
+
+// dirPerm and filePerm restrict the cache to the current user.
+const (
+	dirPerm  = 0o700
+	filePerm = 0o600
+)
+
+// Options configures a DiskCache.
+type Options struct {
+	// Dir is the directory holding cache entries. It is created if it
+	// does not exist.
+	Dir string
+	// TTL is how long an entry stays fresh. Zero means entries never
+	// expire.
+	TTL time.Duration
+	// MaxBytes limits the total size of all entries. When a write pushes
+	// the cache over the limit, the oldest entries are removed until the
+	// cache is a tenth below it. Zero means no limit.
+	MaxBytes int64
+	// Refresh makes every lookup miss so that responses are fetched
+	// again and rewritten, without disabling the cache for writes.
+	Refresh bool
+}
+
+// DiskCache stores response bodies as files named after the SHA-256 hash
+// of their key. It is safe for concurrent use.
+type DiskCache struct {
+	opts Options
+
+	// mu guards total, the size of the entries as of the last prune plus
+	// the entries written since. Entries written by other processes are
+	// only counted at the next prune.
+	mu    sync.Mutex
+	total int64
+}
+
+// DefaultDir returns the default cache directory, "polyhymnia/responses"
+// under the user's cache directory ($XDG_CACHE_HOME or ~/.cache on
+// Linux).
+func DefaultDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+
+	return filepath.Join(cacheDir, "polyhymnia", "responses"), nil
+}
+
+// New returns a DiskCache configured with opts, creating its directory
+// and pruning stale temporary files, expired entries and entries over
+// the size limit.
+func New(opts Options) (*DiskCache, error) {
+	if err := os.MkdirAll(opts.Dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	cache := &DiskCache{opts: opts}
+	if err := cache.prune(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// Get returns the cached body for key if it exists and is still fresh.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	if c.opts.Refresh {
+		return nil, false
+	}
+
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil || c.expired(info.ModTime(), time.Now()) {
+		return nil, false
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return body, true
+}
+
+// Set stores body under key and prunes the cache if the write takes it
+// over its size limit.
+func (c *DiskCache) Set(key string, body []byte) error {
+	tmp, err := os.CreateTemp(c.opts.Dir, tempPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+
+	_, writeErr := tmp.Write(body)
+	closeErr := tmp.Close()
+
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), filePerm); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	path := c.path(key)
+
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+
+	// Rename into place so that concurrent readers never see a
+	// partially written entry.
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		_ = os.Remove(tmp.Name())
+
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total += int64(len(body)) - replaced
+	if c.opts.MaxBytes > 0 && c.total > c.opts.MaxBytes {
+		return c.pruneLocked()
+	}
+
+	return nil
+}
+
+// path returns the file path of the entry for key.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.opts.Dir, hex.EncodeToString(sum[:])+entrySuffix)
+}
+
+// expired reports whether an entry last written at modTime is stale at
+// now.
+func (c *DiskCache) expired(modTime, now time.Time) bool {
+	return c.opts.TTL > 0 && now.Sub(modTime) > c.opts.TTL
+}
+
+// entry describes a cache file for pruning.
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries lists the cache entries, oldest first.
+func (c *DiskCache) entries() ([]entry, error) {
+	dirEntries, err := os.ReadDir(c.opts.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	entries := make([]entry, 0, len(dirEntries))
+
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || !strings.HasSuffix(dirEntry.Name(), entrySuffix) {
+			continue
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue // Removed concurrently.
+		}
+
+		entries = append(entries, entry{
+			path:    filepath.Join(c.opts.Dir, dirEntry.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	return entries, nil
+}
+
+// removeStaleTemps removes the temporary files of entries whose writer
+// stopped before renaming them into place, which would otherwise escape
+// the size limit. Files younger than staleTempAge may still be in use.
+func (c *DiskCache) removeStaleTemps() {
+	temps, err := filepath.Glob(filepath.Join(c.opts.Dir, tempPrefix+"*"))
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+
+	for _, temp := range temps {
+		if info, err := os.Stat(temp); err == nil && now.Sub(info.ModTime()) > staleTempAge {
+			_ = os.Remove(temp)
+		}
+	}
+}
+
+// prune removes stale temporary files, expired entries and then the
+// oldest entries until the cache fits within pruneRatio of MaxBytes.
+func (c *DiskCache) prune() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.pruneLocked()
+}
+
+// pruneLocked prunes the cache with c.mu held and recounts its total
+// size.
+func (c *DiskCache) pruneLocked() error {
+	c.removeStaleTemps()
+
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+
+	defer func() { c.total = total }()
+
+	target := int64(float64(c.opts.MaxBytes) * pruneRatio)
+	now := time.Now()
+
+	for _, e := range entries {
+		oversize := c.opts.MaxBytes > 0 && total > target
+
+		if !c.expired(e.modTime, now) && !oversize {
+			continue
+		}
+
+		if err := os.Remove(e.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+
+		total -= e.size
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package respcache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/respcache"
+	"github.com/stretchr/testify/require"
+)
+
+const testKey = "https://api.datamuse.com/words?ml=ocean"
+
+func TestDiskCache_SetAndGet(t *testing.T) {
+	t.Parallel()
+
+	cache, err := respcache.New(respcache.Options{Dir: t.TempDir(), TTL: time.Hour})
+	require.NoError(t, err)
+
+	_, ok := cache.Get(testKey)
+	require.False(t, ok)
+
+	require.NoError(t, cache.Set(testKey, []byte(`[{"word":"sea"}]`)))
+
+	body, ok := cache.Get(testKey)
+	require.True(t, ok)
+	require.JSONEq(t, `[{"word":"sea"}]`, string(body))
+}
+
+func TestDiskCache_ExpiredEntryMisses(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cache, err := respcache.New(respcache.Options{Dir: dir, TTL: time.Minute})
+	require.NoError(t, err)
+	require.NoError(t, cache.Set(testKey, []byte(`[]`)))
+
+	ageEntries(t, dir, time.Hour)
+
+	_, ok := cache.Get(testKey)
+	require.False(t, ok)
+}
+
+func TestDiskCache_RefreshMissesButWrites(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	refreshing, err := respcache.New(respcache.Options{Dir: dir, Refresh: true})
+	require.NoError(t, err)
+	require.NoError(t, refreshing.Set(testKey, []byte(`[]`)))
+
+	_, ok := refreshing.Get(testKey)
+	require.False(t, ok)
+
+	cache, err := respcache.New(respcache.Options{Dir: dir})
+	require.NoError(t, err)
+
+	_, ok = cache.Get(testKey)
+	require.True(t, ok)
+}
+
+func TestDiskCache_PrunesOldestOverMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	cache, err := respcache.New(respcache.Options{Dir: dir, MaxBytes: 10})
+	require.NoError(t, err)
+	require.NoError(t, cache.Set("old", []byte("123456")))
+
+	ageEntries(t, dir, time.Minute)
+
+	require.NoError(t, cache.Set("new", []byte("abcdef")))
+
+	_, ok := cache.Get("old")
+	require.False(t, ok)
+
+	body, ok := cache.Get("new")
+	require.True(t, ok)
+	require.Equal(t, "abcdef", string(body))
+}
+
+// TestDiskCache_CountsExistingEntries tests that the entries present
+// when the cache is opened count toward the size limit.
+func TestDiskCache_CountsExistingEntries(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	earlier, err := respcache.New(respcache.Options{Dir: dir, MaxBytes: 10})
+	require.NoError(t, err)
+	require.NoError(t, earlier.Set("old", []byte("123456")))
+	require.NoError(t, earlier.Set("old", []byte("654321")))
+
+	ageEntries(t, dir, time.Minute)
+
+	cache, err := respcache.New(respcache.Options{Dir: dir, MaxBytes: 10})
+	require.NoError(t, err)
+
+	_, ok := cache.Get("old")
+	require.True(t, ok)
+
+	require.NoError(t, cache.Set("new", []byte("abcdef")))
+
+	_, ok = cache.Get("old")
+	require.False(t, ok)
+}
+
+func TestDiskCache_PrunesStaleTempFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	stale := filepath.Join(dir, "tmp-stale")
+	require.NoError(t, os.WriteFile(stale, []byte("partial"), 0o600))
+	ageEntries(t, dir, 2*time.Minute)
+
+	fresh := filepath.Join(dir, "tmp-fresh")
+	require.NoError(t, os.WriteFile(fresh, []byte("partial"), 0o600))
+
+	_, err := respcache.New(respcache.Options{Dir: dir})
+	require.NoError(t, err)
+
+	require.NoFileExists(t, stale)
+	require.FileExists(t, fresh)
+}
+
+// ageEntries moves the modification time of every file in dir back by
+// age.
+func ageEntries(t *testing.T, dir string, age time.Duration) {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"))
+	require.NoError(t, err)
+
+	past := time.Now().Add(-age)
+	for _, path := range paths {
+		require.NoError(t, os.Chtimes(path, past, past))
+	}
+}
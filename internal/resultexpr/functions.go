@@ -0,0 +1,95 @@
+package resultexpr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// function is a builtin function callable from expressions.
+type function struct {
+	result kind
+	check  func(kinds []kind) error
+	call   func(args []value, kinds []kind) value
+}
+
+// numbers returns a check that accepts n numbers, or at least one
+// number when n is negative.
+func numbers(n int) func(kinds []kind) error {
+	return func(kinds []kind) error {
+		switch {
+		case n >= 0 && len(kinds) != n:
+			return fmt.Errorf("expected %d arguments, got %d", n, len(kinds))
+		case n < 0 && len(kinds) == 0:
+			return errors.New("expected at least one argument")
+		}
+
+		for _, k := range kinds {
+			if k != kindNumber {
+				return fmt.Errorf("expected numbers, got a %s", k)
+			}
+		}
+
+		return nil
+	}
+}
+
+// one returns a check that accepts a single argument of one of kinds.
+func one(accepted ...kind) func(kinds []kind) error {
+	return func(kinds []kind) error {
+		if len(kinds) != 1 {
+			return fmt.Errorf("expected 1 argument, got %d", len(kinds))
+		}
+
+		for _, k := range accepted {
+			if kinds[0] == k {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("cannot be applied to a %s", kinds[0])
+	}
+}
+
+// math1 wraps a single-argument math function.
+func math1(fn func(float64) float64) function {
+	return function{kindNumber, numbers(1), func(args []value, _ []kind) value {
+		return value{num: fn(args[0].num)}
+	}}
+}
+
+// fold returns a function reducing its numeric arguments with fn.
+func fold(fn func(a, b float64) float64) function {
+	return function{kindNumber, numbers(-1), func(args []value, _ []kind) value {
+		acc := args[0].num
+		for _, arg := range args[1:] {
+			acc = fn(acc, arg.num)
+		}
+
+		return value{num: acc}
+	}}
+}
+
+// functions maps function names to their definitions.
+//
+//nolint:gochecknoglobals
+var functions = map[string]function{
+	// len returns the number of characters of a string or items of a list.
+	"len": {kindNumber, one(kindString, kindList), func(args []value, kinds []kind) value {
+		if kinds[0] == kindList {
+			return value{num: float64(len(args[0].list))}
+		}
+
+		return value{num: float64(utf8.RuneCountInString(args[0].str))}
+	}},
+	"abs":  math1(math.Abs),
+	"log":  math1(math.Log),
+	"sqrt": math1(math.Sqrt),
+	"min":  fold(math.Min),
+	"max":  fold(math.Max),
+	"lower": {kindString, one(kindString), func(args []value, _ []kind) value {
+		return value{str: strings.ToLower(args[0].str)}
+	}},
+}
@@ -0,0 +1,146 @@
+package resultexpr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tokenKind classifies a token.
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOperator
+)
+
+// token is a lexical token with its starting byte offset in the source.
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	str  string
+	pos  int
+}
+
+// operators lists the operator and punctuation tokens, longest first so
+// that "<=" is not read as "<" followed by "=".
+//
+//nolint:gochecknoglobals
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=", "!~",
+	"<", ">", "!", "~", "+", "-", "*", "/", "%", "(", ")", ",",
+}
+
+// lex splits src into tokens, ending with a tokEOF token.
+func lex(src string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(src); {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+		case r >= '0' && r <= '9' || r == '.':
+			tok, err := lexNumber(src, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			pos += len(tok.text)
+		case r == '"' || r == '\'':
+			tok, err := lexString(src, pos)
+			if err != nil {
+				return nil, err
+			}
+
+			tokens = append(tokens, tok)
+			pos += len(tok.text)
+		case unicode.IsLetter(r) || r == '_':
+			end := pos
+			for end < len(src) {
+				r, size := utf8.DecodeRuneInString(src[end:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+					break
+				}
+
+				end += size
+			}
+
+			tokens = append(tokens, token{kind: tokIdent, text: src[pos:end], pos: pos})
+			pos = end
+		default:
+			op := matchOperator(src[pos:])
+			if op == "" {
+				return nil, syntaxError(pos, fmt.Sprintf("unexpected character %q", r))
+			}
+
+			tokens = append(tokens, token{kind: tokOperator, text: op, pos: pos})
+			pos += len(op)
+		}
+	}
+
+	return append(tokens, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+// matchOperator returns the operator at the start of s, or "".
+func matchOperator(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+// lexNumber reads a decimal number such as 2, 1.5 or .5 at pos.
+func lexNumber(src string, pos int) (token, error) {
+	end := pos
+	for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.') {
+		end++
+	}
+
+	num, err := strconv.ParseFloat(src[pos:end], 64)
+	if err != nil {
+		return token{}, syntaxError(pos, fmt.Sprintf("invalid number %q", src[pos:end]))
+	}
+
+	return token{kind: tokNumber, text: src[pos:end], num: num, pos: pos}, nil
+}
+
+// lexString reads a quoted string at pos. Double-quoted strings accept
+// Go escape sequences; single-quoted strings are taken literally, which
+// suits regular expressions.
+func lexString(src string, pos int) (token, error) {
+	quote := src[pos]
+
+	for end := pos + 1; end < len(src); end++ {
+		switch {
+		case src[end] == '\\' && quote == '"':
+			end++
+		case src[end] == quote:
+			text := src[pos : end+1]
+
+			if quote == '\'' {
+				return token{kind: tokString, text: text, str: text[1 : len(text)-1], pos: pos}, nil
+			}
+
+			str, err := strconv.Unquote(text)
+			if err != nil {
+				return token{}, syntaxError(pos, fmt.Sprintf("invalid string %s", text))
+			}
+
+			return token{kind: tokString, text: text, str: str, pos: pos}, nil
+		}
+	}
+
+	return token{}, syntaxError(pos, "unterminated string")
+}
@@ -0,0 +1,577 @@
+package resultexpr
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// errDivisionByZero reports a division or remainder by zero.
+var errDivisionByZero = errors.New("division by zero")
+
+// node is a type-checked expression tree node.
+type node interface {
+	kind() kind
+	eval(result *datamuseapi.APIResponse) (value, error)
+}
+
+// parser is a recursive-descent parser over the tokens of an expression.
+// Precedence from lowest to highest is ||, &&, !, comparisons, + -,
+// * / %, unary minus.
+type parser struct {
+	tokens []token
+	i      int
+	fields map[string]bool // Fields referred to by the expression
+}
+
+// peek returns the current token.
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+// next returns the current token and advances past it.
+func (p *parser) next() token {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+
+	return tok
+}
+
+// isOperator reports whether the current token is one of ops.
+func (p *parser) isOperator(ops ...string) bool {
+	tok := p.peek()
+
+	return tok.kind == tokOperator && slices.Contains(ops, tok.text)
+}
+
+// parseExpr parses a complete expression.
+func (p *parser) parseExpr() (node, error) {
+	return p.parseLogical("||", p.parseAnd)
+}
+
+// parseAnd parses a conjunction.
+func (p *parser) parseAnd() (node, error) {
+	return p.parseLogical("&&", p.parseNot)
+}
+
+// parseLogical parses operands joined by the logical operator op.
+func (p *parser) parseLogical(op string, operand func() (node, error)) (node, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(op) {
+		tok := p.next()
+
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		if x.kind() != kindBool || y.kind() != kindBool {
+			return nil, typeError(tok.pos, fmt.Sprintf("%s needs booleans, got %s and %s", op, x.kind(), y.kind()))
+		}
+
+		x = &logicalNode{and: op == "&&", x: x, y: y}
+	}
+
+	return x, nil
+}
+
+// parseNot parses an optionally negated comparison.
+func (p *parser) parseNot() (node, error) {
+	if !p.isOperator("!") {
+		return p.parseComparison()
+	}
+
+	tok := p.next()
+
+	x, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+
+	if x.kind() != kindBool {
+		return nil, typeError(tok.pos, fmt.Sprintf("! needs a boolean, got a %s", x.kind()))
+	}
+
+	return &notNode{x: x}, nil
+}
+
+// parseComparison parses an arithmetic expression optionally compared to
+// another. Comparisons do not chain.
+func (p *parser) parseComparison() (node, error) {
+	x, err := p.parseArithmetic(p.parseTerm, "+", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.peek()
+
+	isHas := tok.kind == tokIdent && tok.text == "has"
+	if !isHas && !p.isOperator("==", "!=", "<", "<=", ">", ">=", "~", "!~") {
+		return x, nil
+	}
+
+	p.next()
+
+	y, err := p.parseArithmetic(p.parseTerm, "+", "-")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case isHas:
+		return newHasNode(tok, x, y)
+	case tok.text == "~" || tok.text == "!~":
+		return newMatchNode(tok, x, y)
+	default:
+		return newCompareNode(tok, x, y)
+	}
+}
+
+// parseTerm parses a product.
+func (p *parser) parseTerm() (node, error) {
+	return p.parseArithmetic(p.parseUnary, "*", "/", "%")
+}
+
+// parseArithmetic parses operands joined by the arithmetic operators ops.
+func (p *parser) parseArithmetic(operand func() (node, error), ops ...string) (node, error) {
+	x, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.isOperator(ops...) {
+		tok := p.next()
+
+		y, err := operand()
+		if err != nil {
+			return nil, err
+		}
+
+		k := x.kind()
+
+		switch {
+		case k == kindNumber && y.kind() == kindNumber:
+		case k == kindString && y.kind() == kindString && tok.text == "+":
+		default:
+			return nil, typeError(tok.pos, fmt.Sprintf("cannot apply %s to %s and %s", tok.text, x.kind(), y.kind()))
+		}
+
+		x = &arithmeticNode{op: tok.text, x: x, y: y, k: k}
+	}
+
+	return x, nil
+}
+
+// parseUnary parses an optionally negated primary expression.
+func (p *parser) parseUnary() (node, error) {
+	if !p.isOperator("-") {
+		return p.parsePrimary()
+	}
+
+	tok := p.next()
+
+	x, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	if x.kind() != kindNumber {
+		return nil, typeError(tok.pos, fmt.Sprintf("cannot negate a %s", x.kind()))
+	}
+
+	return &negateNode{x: x}, nil
+}
+
+// parsePrimary parses a literal, field, function call or parenthesized
+// expression.
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokNumber:
+		return &literalNode{k: kindNumber, v: value{num: tok.num}}, nil
+	case tokString:
+		return &literalNode{k: kindString, v: value{str: tok.str}}, nil
+	case tokIdent:
+		return p.parseIdent(tok)
+	case tokOperator:
+		if tok.text != "(" {
+			break
+		}
+
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.text != ")" || closing.kind != tokOperator {
+			return nil, syntaxError(closing.pos, "expected )")
+		}
+
+		return x, nil
+	case tokEOF:
+		return nil, syntaxError(tok.pos, "unexpected end of expression")
+	}
+
+	return nil, syntaxError(tok.pos, fmt.Sprintf("unexpected %q", tok.text))
+}
+
+// parseIdent parses a boolean literal, field or function call starting
+// with the identifier tok.
+func (p *parser) parseIdent(tok token) (node, error) {
+	switch tok.text {
+	case "true", "false":
+		return &literalNode{k: kindBool, v: value{b: tok.text == "true"}}, nil
+	}
+
+	if p.isOperator("(") {
+		return p.parseCall(tok)
+	}
+
+	f, ok := fields[tok.text]
+	if !ok {
+		return nil, syntaxError(tok.pos, fmt.Sprintf("unknown field %q (expected one of %s)",
+			tok.text, strings.Join(fieldNames, ", ")))
+	}
+
+	p.fields[tok.text] = true
+
+	return &fieldNode{f}, nil
+}
+
+// parseCall parses the arguments of a call to the function named by tok.
+func (p *parser) parseCall(tok token) (node, error) {
+	fn, ok := functions[tok.text]
+	if !ok {
+		return nil, syntaxError(tok.pos, fmt.Sprintf("unknown function %q (expected one of %s)",
+			tok.text, strings.Join(slices.Sorted(maps.Keys(functions)), ", ")))
+	}
+
+	p.next() // (
+
+	var args []node
+
+	for !p.isOperator(")") {
+		if len(args) > 0 {
+			if comma := p.next(); comma.text != "," || comma.kind != tokOperator {
+				return nil, syntaxError(comma.pos, "expected , or )")
+			}
+		}
+
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	p.next() // )
+
+	kinds := make([]kind, len(args))
+	for i, arg := range args {
+		kinds[i] = arg.kind()
+	}
+
+	if err := fn.check(kinds); err != nil {
+		return nil, typeError(tok.pos, fmt.Sprintf("%s: %v", tok.text, err))
+	}
+
+	return &callNode{fn: fn, args: args, kinds: kinds}, nil
+}
+
+// literalNode is a constant.
+type literalNode struct {
+	k kind
+	v value
+}
+
+func (n *literalNode) kind() kind { return n.k }
+
+func (n *literalNode) eval(*datamuseapi.APIResponse) (value, error) {
+	return n.v, nil
+}
+
+// fieldNode reads a field of the result.
+type fieldNode struct {
+	field
+}
+
+func (n *fieldNode) kind() kind { return n.field.kind }
+
+func (n *fieldNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	return n.get(result), nil
+}
+
+// notNode negates a boolean.
+type notNode struct {
+	x node
+}
+
+func (n *notNode) kind() kind { return kindBool }
+
+func (n *notNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+
+	return value{b: !x.b}, err
+}
+
+// negateNode negates a number.
+type negateNode struct {
+	x node
+}
+
+func (n *negateNode) kind() kind { return kindNumber }
+
+func (n *negateNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+
+	return value{num: -x.num}, err
+}
+
+// logicalNode is a short-circuit && or ||.
+type logicalNode struct {
+	and  bool
+	x, y node
+}
+
+func (n *logicalNode) kind() kind { return kindBool }
+
+func (n *logicalNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+	if err != nil || x.b != n.and {
+		return x, err
+	}
+
+	return n.y.eval(result)
+}
+
+// compareNode compares two numbers, strings or booleans.
+type compareNode struct {
+	op   string
+	x, y node
+}
+
+// newCompareNode type-checks a comparison.
+func newCompareNode(tok token, x, y node) (node, error) {
+	k := x.kind()
+
+	switch {
+	case k != y.kind() || k == kindList:
+		return nil, typeError(tok.pos, fmt.Sprintf("cannot compare %s and %s", x.kind(), y.kind()))
+	case k == kindBool && tok.text != "==" && tok.text != "!=":
+		return nil, typeError(tok.pos, fmt.Sprintf("cannot order booleans with %s", tok.text))
+	}
+
+	return &compareNode{op: tok.text, x: x, y: y}, nil
+}
+
+func (n *compareNode) kind() kind { return kindBool }
+
+func (n *compareNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	y, err := n.y.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	var order int
+
+	switch n.x.kind() {
+	case kindNumber:
+		order = cmp.Compare(x.num, y.num)
+	case kindString:
+		order = strings.Compare(x.str, y.str)
+	case kindBool:
+		if x.b != y.b {
+			order = 1
+		}
+	case kindList:
+	}
+
+	switch n.op {
+	case "==":
+		return value{b: order == 0}, nil
+	case "!=":
+		return value{b: order != 0}, nil
+	case "<":
+		return value{b: order < 0}, nil
+	case "<=":
+		return value{b: order <= 0}, nil
+	case ">":
+		return value{b: order > 0}, nil
+	default:
+		return value{b: order >= 0}, nil
+	}
+}
+
+// matchNode matches a string against a regular expression.
+type matchNode struct {
+	negate bool
+	x, y   node
+	re     *regexp.Regexp // Compiled when parsing if the pattern is a literal
+}
+
+// newMatchNode type-checks a regular expression match.
+func newMatchNode(tok token, x, y node) (node, error) {
+	if x.kind() != kindString || y.kind() != kindString {
+		return nil, typeError(tok.pos, fmt.Sprintf("%s needs strings, got %s and %s", tok.text, x.kind(), y.kind()))
+	}
+
+	n := &matchNode{negate: tok.text == "!~", x: x, y: y}
+
+	if literal, ok := y.(*literalNode); ok {
+		re, err := regexp.Compile(literal.v.str)
+		if err != nil {
+			return nil, syntaxError(tok.pos, fmt.Sprintf("invalid regular expression: %v", err))
+		}
+
+		n.re = re
+	}
+
+	return n, nil
+}
+
+func (n *matchNode) kind() kind { return kindBool }
+
+func (n *matchNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	re := n.re
+	if re == nil {
+		y, err := n.y.eval(result)
+		if err != nil {
+			return value{}, err
+		}
+
+		if re, err = regexp.Compile(y.str); err != nil {
+			return value{}, fmt.Errorf("invalid regular expression: %w", err)
+		}
+	}
+
+	return value{b: re.MatchString(x.str) != n.negate}, nil
+}
+
+// hasNode tests whether a list contains a string or a string contains a
+// substring.
+type hasNode struct {
+	x, y node
+}
+
+// newHasNode type-checks a has test.
+func newHasNode(tok token, x, y node) (node, error) {
+	if (x.kind() != kindList && x.kind() != kindString) || y.kind() != kindString {
+		return nil, typeError(tok.pos, fmt.Sprintf("has needs a list or string and a string, got %s and %s",
+			x.kind(), y.kind()))
+	}
+
+	return &hasNode{x: x, y: y}, nil
+}
+
+func (n *hasNode) kind() kind { return kindBool }
+
+func (n *hasNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	y, err := n.y.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	if n.x.kind() == kindList {
+		return value{b: slices.Contains(x.list, y.str)}, nil
+	}
+
+	return value{b: strings.Contains(x.str, y.str)}, nil
+}
+
+// arithmeticNode applies + - * / % to numbers, or + to strings.
+type arithmeticNode struct {
+	op   string
+	x, y node
+	k    kind
+}
+
+func (n *arithmeticNode) kind() kind { return n.k }
+
+func (n *arithmeticNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	x, err := n.x.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	y, err := n.y.eval(result)
+	if err != nil {
+		return value{}, err
+	}
+
+	if n.k == kindString {
+		return value{str: x.str + y.str}, nil
+	}
+
+	switch n.op {
+	case "+":
+		return value{num: x.num + y.num}, nil
+	case "-":
+		return value{num: x.num - y.num}, nil
+	case "*":
+		return value{num: x.num * y.num}, nil
+	}
+
+	if y.num == 0 {
+		return value{}, errDivisionByZero
+	}
+
+	if n.op == "/" {
+		return value{num: x.num / y.num}, nil
+	}
+
+	return value{num: math.Mod(x.num, y.num)}, nil
+}
+
+// callNode calls a function.
+type callNode struct {
+	fn    function
+	args  []node
+	kinds []kind
+}
+
+func (n *callNode) kind() kind { return n.fn.result }
+
+func (n *callNode) eval(result *datamuseapi.APIResponse) (value, error) {
+	args := make([]value, len(n.args))
+
+	for i, arg := range n.args {
+		v, err := arg.eval(result)
+		if err != nil {
+			return value{}, err
+		}
+
+		args[i] = v
+	}
+
+	return n.fn.call(args, n.kinds), nil
+}
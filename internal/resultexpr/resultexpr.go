@@ -0,0 +1,174 @@
+// Package resultexpr evaluates small expressions over Datamuse results,
+// such as `syllables == 2 && freq > 5 && pos has "adj"`, to filter and
+// rank them on the client.
+//
+// Expressions refer to the fields word, score, syllables, freq, pos,
+// pron and defs of a result and combine them with the operators
+// || && ! == != < <= > >= ~ !~ has + - * / %, parentheses and the
+// functions len, abs, log, sqrt, min, max and lower. The operator ~
+// matches a regular expression and has tests whether a list contains a
+// string or a string contains a substring. Expressions are type-checked
+// when parsed, cannot loop and have no side effects, so they are safe to
+// accept from the command line.
+package resultexpr
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+var (
+	// ErrSyntax reports an expression that cannot be parsed.
+	ErrSyntax = errors.New("syntax error")
+	// ErrType reports an operator or function applied to values of the
+	// wrong type, or an expression of the wrong type for its use.
+	ErrType = errors.New("type error")
+	// ErrEval reports an expression that failed for a result, such as a
+	// division by zero.
+	ErrEval = errors.New("evaluation error")
+)
+
+// syntaxError returns an ErrSyntax for the byte offset pos.
+func syntaxError(pos int, msg string) error {
+	return fmt.Errorf("%w at column %d: %s", ErrSyntax, pos+1, msg)
+}
+
+// typeError returns an ErrType for the byte offset pos.
+func typeError(pos int, msg string) error {
+	return fmt.Errorf("%w at column %d: %s", ErrType, pos+1, msg)
+}
+
+// Expr is a parsed expression.
+type Expr struct {
+	src    string
+	root   node
+	fields map[string]bool
+}
+
+// parse parses src and checks that it yields a value of kind want.
+func parse(src string, want kind) (*Expr, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: make(map[string]bool)}
+
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, syntaxError(tok.pos, fmt.Sprintf("unexpected %q", tok.text))
+	}
+
+	if root.kind() != want {
+		return nil, fmt.Errorf("%w: expression is a %s, expected a %s", ErrType, root.kind(), want)
+	}
+
+	return &Expr{src: src, root: root, fields: p.fields}, nil
+}
+
+// ParseCondition parses a boolean expression used to filter results.
+func ParseCondition(src string) (*Expr, error) {
+	return parse(src, kindBool)
+}
+
+// ParseScore parses a numeric expression used to rank results.
+func ParseScore(src string) (*Expr, error) {
+	return parse(src, kindNumber)
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Metadata returns the Datamuse metadata letters (as used in the md
+// parameter) needed to evaluate the expression, such as "f" when it
+// refers to freq.
+func (e *Expr) Metadata() string {
+	var letters string
+
+	for _, field := range fieldNames {
+		if letter := fields[field].metadata; e.fields[field] && letter != "" {
+			letters += letter
+		}
+	}
+
+	return letters
+}
+
+// Match reports whether result satisfies a condition.
+func (e *Expr) Match(result datamuseapi.APIResponse) (bool, error) {
+	v, err := e.root.eval(&result)
+	if err != nil {
+		return false, fmt.Errorf("%w for %q: %w", ErrEval, result.Word, err)
+	}
+
+	return v.b, nil
+}
+
+// Score evaluates a numeric expression for result.
+func (e *Expr) Score(result datamuseapi.APIResponse) (float64, error) {
+	v, err := e.root.eval(&result)
+	if err != nil {
+		return 0, fmt.Errorf("%w for %q: %w", ErrEval, result.Word, err)
+	}
+
+	return v.num, nil
+}
+
+// Filter returns the results that satisfy the condition, in their
+// original order.
+func Filter(results []datamuseapi.APIResponse, condition *Expr) ([]datamuseapi.APIResponse, error) {
+	kept := make([]datamuseapi.APIResponse, 0, len(results))
+
+	for _, result := range results {
+		ok, err := condition.Match(result)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			kept = append(kept, result)
+		}
+	}
+
+	return kept, nil
+}
+
+// Rank orders results by the score expression, highest first. Results
+// with equal scores keep their order.
+func Rank(results []datamuseapi.APIResponse, score *Expr) error {
+	type scored struct {
+		result datamuseapi.APIResponse
+		score  float64
+	}
+
+	ranked := make([]scored, len(results))
+
+	for i, result := range results {
+		value, err := score.Score(result)
+		if err != nil {
+			return err
+		}
+
+		ranked[i] = scored{result, value}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	for i, r := range ranked {
+		results[i] = r.result
+	}
+
+	return nil
+}
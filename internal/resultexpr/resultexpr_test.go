@@ -0,0 +1,134 @@
+package resultexpr_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultexpr"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleResults returns enriched results in Datamuse score order.
+func sampleResults() []datamuseapi.APIResponse {
+	return []datamuseapi.APIResponse{
+		{Word: "sea", Score: 1001, NumSyllables: 1, Frequency: 120.5, Tags: []string{"syn", "n"},
+			Pronunciation: "S IY1 ", Definitions: []string{"n\tA large body of salt water."}},
+		{Word: "briny", Score: 998, NumSyllables: 2, Frequency: 6.7, Tags: []string{"adj", "n"}},
+		{Word: "high-seas", Score: 700, NumSyllables: 2, Frequency: 9.2, Tags: []string{"adj"}},
+		{Word: "oceanic", Score: 650, NumSyllables: 4, Frequency: 2.1, Tags: []string{"adj"}},
+	}
+}
+
+// words returns the words of results in order.
+func words(results []datamuseapi.APIResponse) []string {
+	words := make([]string, len(results))
+	for i, result := range results {
+		words[i] = result.Word
+	}
+
+	return words
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		where    string
+		expected []string
+	}{
+		{`syllables == 2 && freq > 5 && pos has "adj" && word !~ "-"`, []string{"briny"}},
+		{`syllables >= 2 || word == "sea"`, []string{"sea", "briny", "high-seas", "oceanic"}},
+		{`!(pos has "n")`, []string{"high-seas", "oceanic"}},
+		{`word ~ '^[a-c]'`, []string{"briny"}},
+		{`len(word) > 5 && len(pos) == 1`, []string{"high-seas", "oceanic"}},
+		{`pron == "S IY1" && defs has "n\tA large body of salt water."`, []string{"sea"}},
+		{`word has "ea"`, []string{"sea", "high-seas", "oceanic"}},
+		{`score % 2 == 1 && -score < -1000`, []string{"sea"}},
+		{`lower("SEA") == word || max(1, freq, 3) == 3`, []string{"sea", "oceanic"}},
+		{`true`, []string{"sea", "briny", "high-seas", "oceanic"}},
+	}
+
+	for _, test := range tests {
+		condition, err := resultexpr.ParseCondition(test.where)
+		require.NoError(t, err, test.where)
+
+		kept, err := resultexpr.Filter(sampleResults(), condition)
+		require.NoError(t, err, test.where)
+		require.Equal(t, test.expected, words(kept), test.where)
+	}
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+
+	score, err := resultexpr.ParseScore("log(freq + 1) * 10 - syllables")
+	require.NoError(t, err)
+
+	results := sampleResults()
+	require.NoError(t, resultexpr.Rank(results, score))
+	require.Equal(t, []string{"sea", "high-seas", "briny", "oceanic"}, words(results))
+
+	// Equal scores keep their order.
+	score, err = resultexpr.ParseScore("syllables * 0")
+	require.NoError(t, err)
+
+	results = sampleResults()
+	require.NoError(t, resultexpr.Rank(results, score))
+	require.Equal(t, words(sampleResults()), words(results))
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr     string
+		expected error
+	}{
+		{`syllables ==`, resultexpr.ErrSyntax},
+		{`syllables == 2 &&`, resultexpr.ErrSyntax},
+		{`rhymes > 2`, resultexpr.ErrSyntax},
+		{`word ~ "("`, resultexpr.ErrSyntax},
+		{`word == "sea`, resultexpr.ErrSyntax},
+		{`(word == "sea"`, resultexpr.ErrSyntax},
+		{`syllables == 2 3`, resultexpr.ErrSyntax},
+		{`word @ "x"`, resultexpr.ErrSyntax},
+		{`nope(word)`, resultexpr.ErrSyntax},
+		{`word > 2`, resultexpr.ErrType},
+		{`pos == "n"`, resultexpr.ErrType},
+		{`syllables && true`, resultexpr.ErrType},
+		{`freq has "n"`, resultexpr.ErrType},
+		{`len(score) > 1`, resultexpr.ErrType},
+		{`min() > 1`, resultexpr.ErrType},
+		{`syllables`, resultexpr.ErrType},
+	}
+
+	for _, test := range tests {
+		_, err := resultexpr.ParseCondition(test.expr)
+		require.ErrorIs(t, err, test.expected, test.expr)
+	}
+
+	_, err := resultexpr.ParseScore(`freq > 1`)
+	require.ErrorIs(t, err, resultexpr.ErrType)
+}
+
+func TestMatch_DivisionByZero(t *testing.T) {
+	t.Parallel()
+
+	condition, err := resultexpr.ParseCondition("score / (syllables - 1) > 1")
+	require.NoError(t, err)
+
+	_, err = resultexpr.Filter(sampleResults(), condition)
+	require.ErrorIs(t, err, resultexpr.ErrEval)
+}
+
+func TestExpr_Metadata(t *testing.T) {
+	t.Parallel()
+
+	condition, err := resultexpr.ParseCondition(`syllables == 2 && freq > 5 && pos has "adj" && word !~ "-"`)
+	require.NoError(t, err)
+	require.Equal(t, "fps", condition.Metadata())
+
+	score, err := resultexpr.ParseScore("score + len(defs) + len(pron)")
+	require.NoError(t, err)
+	require.Equal(t, "dr", score.Metadata())
+}
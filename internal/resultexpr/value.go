@@ -0,0 +1,82 @@
+package resultexpr
+
+import (
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// kind is the type of an expression value.
+type kind int
+
+const (
+	kindNumber kind = iota
+	kindString
+	kindBool
+	kindList
+)
+
+// String returns the name of the kind used in error messages.
+func (k kind) String() string {
+	switch k {
+	case kindNumber:
+		return "number"
+	case kindString:
+		return "string"
+	case kindBool:
+		return "boolean"
+	case kindList:
+		return "list"
+	default:
+		return "unknown"
+	}
+}
+
+// value is the result of evaluating an expression. Only the member for
+// its kind is meaningful.
+type value struct {
+	num  float64
+	str  string
+	b    bool
+	list []string
+}
+
+// field describes a result field that expressions can refer to.
+type field struct {
+	kind     kind
+	metadata string // Datamuse metadata letter that fills the field in
+	get      func(result *datamuseapi.APIResponse) value
+}
+
+// fieldNames lists the fields in the order their metadata letters are
+// reported.
+//
+//nolint:gochecknoglobals
+var fieldNames = []string{"word", "score", "defs", "freq", "pos", "pron", "syllables"}
+
+// fields maps field names to their definitions.
+//
+//nolint:gochecknoglobals
+var fields = map[string]field{
+	"word": {kindString, "", func(r *datamuseapi.APIResponse) value {
+		return value{str: r.Word}
+	}},
+	"score": {kindNumber, "", func(r *datamuseapi.APIResponse) value {
+		return value{num: float64(r.Score)}
+	}},
+	"defs": {kindList, "d", func(r *datamuseapi.APIResponse) value {
+		return value{list: r.Definitions}
+	}},
+	"freq": {kindNumber, "f", func(r *datamuseapi.APIResponse) value {
+		return value{num: r.Frequency}
+	}},
+	"pos": {kindList, "p", func(r *datamuseapi.APIResponse) value {
+		return value{list: r.Tags}
+	}},
+	"pron": {kindString, "r", func(r *datamuseapi.APIResponse) value {
+		return value{str: strings.TrimSpace(r.Pronunciation)}
+	}},
+	"syllables": {kindNumber, "s", func(r *datamuseapi.APIResponse) value {
+		return value{num: float64(r.NumSyllables)}
+	}},
+}
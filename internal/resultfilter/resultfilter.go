@@ -0,0 +1,198 @@
+// Package resultfilter filters Datamuse results on the client by
+// properties the API cannot filter on, such as part of speech or
+// syllable count.
+package resultfilter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
+)
+
+// ErrInvalidRange reports a range that cannot be parsed.
+var ErrInvalidRange = errors.New("invalid range")
+
+// Range is an inclusive range of counts such as syllables or letters. A
+// Max of zero leaves the range open-ended, so the zero Range matches
+// every count.
+type Range struct {
+	Min int
+	Max int
+}
+
+// ParseRange converts "2-3", "2" (exactly two), "2-" (at least two) or
+// "-3" (at most three) to a Range. A maximum of zero is rejected rather
+// than read as an open end, since no word has zero letters or syllables.
+func ParseRange(value string) (Range, error) {
+	low, high, isRange := strings.Cut(strings.TrimSpace(value), "-")
+
+	parse := func(s string) (int, error) {
+		if s == "" {
+			return 0, nil
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w %q: expected N, N-M, N- or -M", ErrInvalidRange, value)
+		}
+
+		return n, nil
+	}
+
+	minimum, err := parse(low)
+	if err != nil {
+		return Range{}, err
+	}
+
+	if !isRange {
+		if low == "" {
+			return Range{}, fmt.Errorf("%w %q: expected N, N-M, N- or -M", ErrInvalidRange, value)
+		}
+
+		if minimum == 0 {
+			return Range{}, fmt.Errorf("%w %q: the count must be at least 1", ErrInvalidRange, value)
+		}
+
+		return Range{Min: minimum, Max: minimum}, nil
+	}
+
+	maximum, err := parse(high)
+	if err != nil {
+		return Range{}, err
+	}
+
+	if strings.TrimSpace(high) != "" && maximum == 0 {
+		return Range{}, fmt.Errorf("%w %q: the maximum must be at least 1", ErrInvalidRange, value)
+	}
+
+	if maximum != 0 && maximum < minimum {
+		return Range{}, fmt.Errorf("%w %q: the minimum exceeds the maximum", ErrInvalidRange, value)
+	}
+
+	return Range{Min: minimum, Max: maximum}, nil
+}
+
+// IsZero reports whether the range matches every count.
+func (r Range) IsZero() bool {
+	return r.Min == 0 && r.Max == 0
+}
+
+// Contains reports whether n lies within the range.
+func (r Range) Contains(n int) bool {
+	return n >= r.Min && (r.Max == 0 || n <= r.Max)
+}
+
+// String returns the range as accepted by ParseRange.
+func (r Range) String() string {
+	switch {
+	case r.IsZero():
+		return ""
+	case r.Min == r.Max:
+		return strconv.Itoa(r.Min)
+	case r.Max == 0:
+		return strconv.Itoa(r.Min) + "-"
+	default:
+		return strconv.Itoa(r.Min) + "-" + strconv.Itoa(r.Max)
+	}
+}
+
+// Set parses value into the range, so that a Range can be used as a
+// command-line flag.
+func (r *Range) Set(value string) error {
+	parsed, err := ParseRange(value)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+
+	return nil
+}
+
+// Type returns the flag type name shown in help text.
+func (r *Range) Type() string {
+	return "range"
+}
+
+// Filter keeps the results that satisfy every criterion set. The zero
+// Filter keeps every result.
+type Filter struct {
+	POS        []string                // Parts of speech, any of which a result must have
+	Syllables  Range                   // Syllable count
+	MinFreq    float64                 // Minimum frequency per million words
+	Length     Range                   // Number of characters in the word
+	SingleWord bool                    // Reject multiword expressions such as "high seas"
+	Stress     phonetics.StressPattern // Stress pattern of the pronunciation
+}
+
+// Active reports whether the filter rejects any results at all.
+func (f *Filter) Active() bool {
+	return len(f.POS) > 0 || !f.Syllables.IsZero() || f.MinFreq > 0 || !f.Length.IsZero() || f.SingleWord ||
+		f.Stress != ""
+}
+
+// Metadata returns the Datamuse metadata letters (as used in the md
+// parameter) needed to apply the filter, such as "p" for parts of speech.
+func (f *Filter) Metadata() string {
+	var letters string
+
+	if len(f.POS) > 0 {
+		letters += "p"
+	}
+
+	if !f.Syllables.IsZero() {
+		letters += "s"
+	}
+
+	if f.MinFreq > 0 {
+		letters += "f"
+	}
+
+	if f.Stress != "" {
+		letters += "r"
+	}
+
+	return letters
+}
+
+// Match reports whether result satisfies the filter.
+func (f *Filter) Match(result datamuseapi.APIResponse) bool {
+	if len(f.POS) > 0 && !slices.ContainsFunc(result.Tags, func(tag string) bool {
+		return slices.ContainsFunc(f.POS, func(pos string) bool { return strings.EqualFold(tag, pos) })
+	}) {
+		return false
+	}
+
+	if f.SingleWord && strings.Contains(result.Word, " ") {
+		return false
+	}
+
+	return f.Syllables.Contains(result.NumSyllables) &&
+		result.Frequency >= f.MinFreq &&
+		f.Length.Contains(utf8.RuneCountInString(result.Word)) &&
+		f.Stress.Match(result.Pronunciation)
+}
+
+// Apply returns the results that satisfy the filter, in their original
+// order.
+func (f *Filter) Apply(results []datamuseapi.APIResponse) []datamuseapi.APIResponse {
+	if !f.Active() {
+		return results
+	}
+
+	kept := make([]datamuseapi.APIResponse, 0, len(results))
+
+	for _, result := range results {
+		if f.Match(result) {
+			kept = append(kept, result)
+		}
+	}
+
+	return kept
+}
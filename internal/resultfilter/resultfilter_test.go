@@ -0,0 +1,104 @@
+package resultfilter_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultfilter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected resultfilter.Range
+		valid    bool
+	}{
+		{"2-3", resultfilter.Range{Min: 2, Max: 3}, true},
+		{"2", resultfilter.Range{Min: 2, Max: 2}, true},
+		{"4-", resultfilter.Range{Min: 4}, true},
+		{"-3", resultfilter.Range{Max: 3}, true},
+		{" 1 - 2 ", resultfilter.Range{Min: 1, Max: 2}, true},
+		{"3-2", resultfilter.Range{}, false},
+		{"two", resultfilter.Range{}, false},
+		{"", resultfilter.Range{}, false},
+		{"1-2-3", resultfilter.Range{}, false},
+		{"0", resultfilter.Range{}, false},
+		{"-0", resultfilter.Range{}, false},
+		{"0-0", resultfilter.Range{}, false},
+		{"0-2", resultfilter.Range{Max: 2}, true},
+	}
+
+	for _, test := range tests {
+		parsed, err := resultfilter.ParseRange(test.value)
+
+		if !test.valid {
+			require.ErrorIs(t, err, resultfilter.ErrInvalidRange, test.value)
+
+			continue
+		}
+
+		require.NoError(t, err, test.value)
+		require.Equal(t, test.expected, parsed, test.value)
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	t.Parallel()
+
+	results := []datamuseapi.APIResponse{
+		{Word: "sea", NumSyllables: 1, Frequency: 120.5, Tags: []string{"syn", "n"}},
+		{Word: "briny", NumSyllables: 2, Frequency: 0.7, Tags: []string{"adj", "n"}},
+		{Word: "oceanic", NumSyllables: 4, Frequency: 2.1, Tags: []string{"adj"}},
+		{Word: "high seas", NumSyllables: 2, Frequency: 1.8, Tags: []string{"n"}},
+		// Pronunciations as parsed from a query with ipa=1, which keeps the
+		// ARPAbet needed by the stress filter.
+		{
+			Word: "away", NumSyllables: 2, Frequency: 500.2, Tags: []string{"adv"},
+			Pronunciation: "AH0 W EY1 ", IPAPronunciation: "əwˈeɪ",
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filter   resultfilter.Filter
+		expected []string
+	}{
+		{"zero filter", resultfilter.Filter{}, []string{"sea", "briny", "oceanic", "high seas", "away"}},
+		{"pos", resultfilter.Filter{POS: []string{"ADJ", "adv"}}, []string{"briny", "oceanic", "away"}},
+		{"syllables", resultfilter.Filter{Syllables: resultfilter.Range{Min: 2, Max: 3}},
+			[]string{"briny", "high seas", "away"}},
+		{"min freq", resultfilter.Filter{MinFreq: 1.5}, []string{"sea", "oceanic", "high seas", "away"}},
+		{"length", resultfilter.Filter{Length: resultfilter.Range{Min: 4, Max: 7}}, []string{"briny", "oceanic", "away"}},
+		{"single word", resultfilter.Filter{SingleWord: true}, []string{"sea", "briny", "oceanic", "away"}},
+		{"stress", resultfilter.Filter{Stress: "01"}, []string{"away"}},
+		{"combined", resultfilter.Filter{POS: []string{"n"}, Syllables: resultfilter.Range{Min: 2}, SingleWord: true},
+			[]string{"briny"}},
+	}
+
+	for _, test := range tests {
+		kept := test.filter.Apply(results)
+
+		words := make([]string, len(kept))
+		for i, result := range kept {
+			words[i] = result.Word
+		}
+
+		require.Equal(t, test.expected, words, test.name)
+	}
+}
+
+func TestFilter_Metadata(t *testing.T) {
+	t.Parallel()
+
+	filter := resultfilter.Filter{
+		POS: []string{"n"}, Syllables: resultfilter.Range{Max: 2}, MinFreq: 1, SingleWord: true, Stress: "01",
+	}
+
+	require.True(t, filter.Active())
+	require.Equal(t, "psfr", filter.Metadata())
+	require.False(t, (&resultfilter.Filter{}).Active())
+	require.Empty(t, (&resultfilter.Filter{Length: resultfilter.Range{Min: 3}}).Metadata())
+}
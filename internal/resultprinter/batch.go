@@ -0,0 +1,95 @@
+package resultprinter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// NoBatchResultsMessage stands in for the results of an input term
+// without results in batch text output.
+const NoBatchResultsMessage = "(no results)"
+
+// BatchPrinter writes the results of a query run for many input terms,
+// labeling the results of each term with the term. It supports the text
+// format, in which each term's results follow a "==> term <==" heading,
+// NDJSON, in which each object carries a "term" field, and TSV, in which
+// the first column holds the term. A term without results is still
+// listed, so that the output can be joined back to the input: in NDJSON
+// as an object with an empty "word" and in TSV as a row with empty
+// columns after the term.
+type BatchPrinter struct {
+	printer *Printer
+	started bool // Whether the TSV header row has been written.
+}
+
+// NewBatchPrinter returns a BatchPrinter that writes results to out and
+// diagnostics to errOut. It fails if the options select a format or
+// template that cannot label results with their input term.
+func NewBatchPrinter(out, errOut io.Writer, options DisplayOptions) (*BatchPrinter, error) {
+	if options.Template != nil {
+		return nil, fmt.Errorf("%w: templates cannot be used in batch mode", ErrInvalidFormat)
+	}
+
+	switch options.Format {
+	case "", FormatText, FormatNDJSON, FormatTSV:
+	default:
+		return nil, fmt.Errorf("%w %q for batch mode: expected one of text|ndjson|tsv",
+			ErrInvalidFormat, options.Format)
+	}
+
+	return &BatchPrinter{printer: NewPrinter(out, errOut, options)}, nil
+}
+
+// Print writes the results for one input term.
+func (b *BatchPrinter) Print(term string, results []datamuseapi.APIResponse) error {
+	p := b.printer
+	results = p.convertPronunciations(results)
+
+	switch p.options.Format {
+	case FormatNDJSON:
+		if len(results) < 1 {
+			if err := p.newJSONEncoder().Encode(jsonResult{Term: term}); err != nil {
+				return fmt.Errorf("failed to write JSON: %w", err)
+			}
+		} else if err := p.printNDJSON(term, results); err != nil {
+			return err
+		}
+	case FormatTSV:
+		rows := delimitedRows(results, p.options)
+		if len(rows) == 1 {
+			rows = append(rows, make([]string, len(rows[0])))
+		}
+
+		for i := range rows {
+			label := term
+			if i == 0 {
+				label = "term"
+			}
+
+			rows[i] = append([]string{label}, rows[i]...)
+		}
+
+		if b.started {
+			rows = rows[1:]
+		}
+
+		b.started = true
+		p.writeTSVRows(rows)
+	default:
+		fmt.Fprintf(p.out, "==> %s <==\n", term)
+
+		if len(results) < 1 {
+			fmt.Fprintf(p.out, "%s\n\n", NoBatchResultsMessage)
+		} else {
+			p.printText(results)
+		}
+	}
+
+	if p.out.err != nil {
+		return fmt.Errorf("failed to write output: %w", p.out.err)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package resultprinter_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchPrinter_Text(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	printer, err := resultprinter.NewBatchPrinter(&out, nil, resultprinter.DisplayOptions{ShowScore: true})
+	require.NoError(t, err)
+	require.NoError(t, printer.Print("ocean", sampleResults()))
+	require.NoError(t, printer.Print("xyzzy", nil))
+
+	require.Equal(t, "==> ocean <==\n"+
+		"sea\n\tScore: 1001\n\n"+
+		"briny\n\tScore: 998\n\n"+
+		"==> xyzzy <==\n(no results)\n\n", out.String())
+}
+
+func TestBatchPrinter_TSV(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	options := resultprinter.DisplayOptions{ShowScore: true, Format: resultprinter.FormatTSV}
+	printer, err := resultprinter.NewBatchPrinter(&out, nil, options)
+	require.NoError(t, err)
+	require.NoError(t, printer.Print("ocean", sampleResults()))
+	require.NoError(t, printer.Print("salt", sampleResults()[:1]))
+	require.NoError(t, printer.Print("xyzzy", nil))
+
+	require.Equal(t, "term\tword\tscore\n"+
+		"ocean\tsea\t1001\n"+
+		"ocean\tbriny\t998\n"+
+		"salt\tsea\t1001\n"+
+		"xyzzy\t\t\n", out.String())
+}
+
+func TestBatchPrinter_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	options := resultprinter.DisplayOptions{Format: resultprinter.FormatNDJSON}
+	printer, err := resultprinter.NewBatchPrinter(&out, nil, options)
+	require.NoError(t, err)
+	require.NoError(t, printer.Print("ocean", sampleResults()[1:]))
+	require.NoError(t, printer.Print("xyzzy", nil))
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"term":"ocean","word":"briny","score":998,"numSyllables":2,`+
+		`"queryURL":"https://api.datamuse.com/words?ml=ocean&md=dfprs&max=2"}`, lines[0])
+	require.JSONEq(t, `{"term":"xyzzy","word":""}`, lines[1])
+}
+
+func TestNewBatchPrinter_UnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	options := resultprinter.DisplayOptions{Format: resultprinter.FormatJSON}
+	_, err := resultprinter.NewBatchPrinter(&bytes.Buffer{}, nil, options)
+	require.ErrorIs(t, err, resultprinter.ErrInvalidFormat)
+}
@@ -0,0 +1,143 @@
+package resultprinter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// DefaultListSeparator joins multiple tags or definitions in a single
+// CSV or TSV field.
+const DefaultListSeparator = "; "
+
+// column is a CSV, TSV or table column: its header, whether it holds a
+// number and how to render a result.
+type column struct {
+	header  string
+	numeric bool
+	value   func(result datamuseapi.APIResponse, separator string) string
+}
+
+// columns returns the word column followed by a column for every field
+// enabled in options, in the order used by the text format. The CSV, TSV
+// and table formats share these columns.
+func (opts *DisplayOptions) columns() []column {
+	columns := []column{{"word", false, func(r datamuseapi.APIResponse, _ string) string { return r.Word }}}
+
+	optional := []struct {
+		enabled bool
+		column
+	}{
+		{opts.ShowScore, column{"score", true, func(r datamuseapi.APIResponse, _ string) string {
+			return strconv.Itoa(r.Score)
+		}}},
+		{opts.ShowSyllables, column{"syllables", true, func(r datamuseapi.APIResponse, _ string) string {
+			return strconv.Itoa(r.NumSyllables)
+		}}},
+		{opts.ShowPronunciation, column{"pronunciation", false, func(r datamuseapi.APIResponse, _ string) string {
+			return strings.TrimSpace(r.Pronunciation)
+		}}},
+		{opts.ShowFrequency, column{"frequency", true, func(r datamuseapi.APIResponse, _ string) string {
+			return strconv.FormatFloat(r.Frequency, 'f', 6, 64)
+		}}},
+		{opts.ShowPOS, column{"pos", false, func(r datamuseapi.APIResponse, sep string) string {
+			return strings.Join(r.Tags, sep)
+		}}},
+		{opts.ShowDefinitions, column{"definitions", false, func(r datamuseapi.APIResponse, sep string) string {
+			definitions := make([]string, len(r.Definitions))
+			for i, def := range r.Definitions {
+				definitions[i] = formatDefinition(def)
+			}
+
+			return strings.Join(definitions, sep)
+		}}},
+	}
+
+	for _, opt := range optional {
+		if opt.enabled {
+			columns = append(columns, opt.column)
+		}
+	}
+
+	return columns
+}
+
+// formatDefinition rewrites a Datamuse definition such as
+// "n\tA large body of water" as "(n) A large body of water".
+func formatDefinition(definition string) string {
+	if pos, text, found := strings.Cut(definition, "\t"); found {
+		return "(" + pos + ") " + text
+	}
+
+	return definition
+}
+
+// listSeparator returns the configured list separator or the default.
+func (opts *DisplayOptions) listSeparator() string {
+	if opts.ListSeparator == "" {
+		return DefaultListSeparator
+	}
+
+	return opts.ListSeparator
+}
+
+// delimitedRows returns the header row and one row per result.
+func delimitedRows(results []datamuseapi.APIResponse, options DisplayOptions) [][]string {
+	columns := options.columns()
+	separator := options.listSeparator()
+	rows := make([][]string, 0, len(results)+1)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.header
+	}
+
+	rows = append(rows, header)
+
+	for _, result := range results {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = col.value(result, separator)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// printCSV writes the results as RFC 4180 CSV with a header row.
+func (p *Printer) printCSV(results []datamuseapi.APIResponse) error {
+	writer := csv.NewWriter(p.out)
+
+	if err := writer.WriteAll(delimitedRows(results, p.options)); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+// tsvEscaper replaces the characters that would break a TSV row.
+//
+//nolint:gochecknoglobals
+var tsvEscaper = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+// printTSV writes the results as tab-separated values with a header
+// row. Tabs and line breaks inside fields are replaced with spaces.
+func (p *Printer) printTSV(results []datamuseapi.APIResponse) {
+	p.writeTSVRows(delimitedRows(results, p.options))
+}
+
+// writeTSVRows writes rows as tab-separated values.
+func (p *Printer) writeTSVRows(rows [][]string) {
+	for _, row := range rows {
+		for i, field := range row {
+			row[i] = tsvEscaper.Replace(field)
+		}
+
+		fmt.Fprintln(p.out, strings.Join(row, "\t"))
+	}
+}
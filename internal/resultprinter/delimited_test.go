@@ -0,0 +1,48 @@
+package resultprinter_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResults_CSV(t *testing.T) {
+	t.Parallel()
+
+	results := sampleResults()
+	results[0].Definitions = append(results[0].Definitions, "n\tThe ocean, in general.")
+
+	output := printToString(t, results, resultprinter.DisplayOptions{
+		Format:          resultprinter.FormatCSV,
+		ShowScore:       true,
+		ShowPOS:         true,
+		ShowDefinitions: true,
+	})
+
+	require.Equal(t, "word,score,pos,definitions\n"+
+		"sea,1001,syn; n,\"(n) A large body of salt water.; (n) The ocean, in general.\"\n"+
+		"briny,998,,\n", output)
+}
+
+func TestPrintResults_TSV(t *testing.T) {
+	t.Parallel()
+
+	results := []datamuseapi.APIResponse{
+		{Word: "sea", NumSyllables: 1, Pronunciation: "S IY1 ", Tags: []string{"syn", "n"}},
+		{Word: "tab\tword", NumSyllables: 2},
+	}
+
+	output := printToString(t, results, resultprinter.DisplayOptions{
+		Format:            resultprinter.FormatTSV,
+		ShowSyllables:     true,
+		ShowPronunciation: true,
+		ShowPOS:           true,
+		ListSeparator:     "|",
+	})
+
+	require.Equal(t, "word\tsyllables\tpronunciation\tpos\n"+
+		"sea\t1\tS IY1\tsyn|n\n"+
+		"tab word\t2\t\t\n", output)
+}
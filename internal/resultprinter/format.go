@@ -0,0 +1,84 @@
+package resultprinter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Format selects how PrintResults renders results.
+type Format string
+
+// Output formats supported by PrintResults.
+const (
+	FormatText   Format = "text"   // Indented, human-oriented text (default)
+	FormatJSON   Format = "json"   // A single JSON document with query metadata
+	FormatNDJSON Format = "ndjson" // One JSON object per result per line
+	FormatCSV    Format = "csv"    // Comma-separated values with a header row
+	FormatTSV    Format = "tsv"    // Tab-separated values with a header row
+	FormatTable  Format = "table"  // Aligned columns, one row per result
+)
+
+// ErrInvalidFormat reports an unsupported output format.
+var ErrInvalidFormat = errors.New("invalid output format")
+
+// formats lists the supported formats in the order shown in help text.
+//
+//nolint:gochecknoglobals
+var formats = []Format{FormatText, FormatJSON, FormatNDJSON, FormatCSV, FormatTSV, FormatTable}
+
+// ParseFormat converts a string such as "json" to a Format.
+func ParseFormat(value string) (Format, error) {
+	for _, format := range formats {
+		if strings.EqualFold(value, string(format)) {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %q: expected one of %s", ErrInvalidFormat, value, FormatNames())
+}
+
+// FormatNames returns the supported format names separated by "|".
+func FormatNames() string {
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+
+	return strings.Join(names, "|")
+}
+
+// String returns the format name, reporting the zero value as text. It
+// implements the pflag.Value interface.
+func (f *Format) String() string {
+	if *f == "" {
+		return string(FormatText)
+	}
+
+	return string(*f)
+}
+
+// Set parses and stores a format name. It implements the pflag.Value
+// interface so a Format can be bound directly to a flag.
+func (f *Format) Set(value string) error {
+	format, err := ParseFormat(value)
+	if err != nil {
+		return err
+	}
+
+	*f = format
+
+	return nil
+}
+
+// Type returns the flag value type name shown in help text.
+func (f *Format) Type() string {
+	return "format"
+}
+
+// Structured reports whether the format is meant for machines rather
+// than people, in which case informational messages such as "no
+// results" must not be mixed into the output.
+func (f Format) Structured() bool {
+	return f != "" && f != FormatText && f != FormatTable
+}
@@ -0,0 +1,103 @@
+package resultprinter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// jsonResult is the machine-readable form of a single result.
+type jsonResult struct {
+	Term          string   `json:"term,omitempty"` // Input term in batch mode.
+	Word          string   `json:"word"`
+	Score         int      `json:"score,omitempty"`
+	NumSyllables  int      `json:"numSyllables,omitempty"`
+	Pronunciation string   `json:"pronunciation,omitempty"`
+	IPA           string   `json:"ipaPronunciation,omitempty"` // As given by Datamuse.
+	Frequency     float64  `json:"frequency,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Definitions   []string `json:"definitions,omitempty"`
+	QueryURL      string   `json:"queryURL,omitempty"`
+}
+
+// jsonQuery is the query metadata included in JSON output.
+type jsonQuery struct {
+	URL   string `json:"url,omitempty"`
+	Count int    `json:"count"`
+}
+
+// jsonDocument is the top-level object written in JSON format.
+type jsonDocument struct {
+	Query   jsonQuery    `json:"query"`
+	Results []jsonResult `json:"results"`
+}
+
+// newJSONResult converts an APIResponse to its machine-readable form.
+func newJSONResult(result datamuseapi.APIResponse) jsonResult {
+	return jsonResult{
+		Word:          result.Word,
+		Score:         result.Score,
+		NumSyllables:  result.NumSyllables,
+		Pronunciation: strings.TrimSpace(result.Pronunciation),
+		IPA:           result.IPAPronunciation,
+		Frequency:     result.Frequency,
+		Tags:          result.Tags,
+		Definitions:   result.Definitions,
+	}
+}
+
+// newJSONEncoder returns an encoder writing to the printer's output that
+// leaves characters such as "&" in query URLs unescaped.
+func (p *Printer) newJSONEncoder() *json.Encoder {
+	encoder := json.NewEncoder(p.out)
+	encoder.SetEscapeHTML(false)
+
+	return encoder
+}
+
+// printJSON writes the results and query metadata as a single indented
+// JSON document.
+func (p *Printer) printJSON(results []datamuseapi.APIResponse) error {
+	document := jsonDocument{
+		Query:   jsonQuery{Count: len(results)},
+		Results: make([]jsonResult, 0, len(results)),
+	}
+
+	if len(results) > 0 {
+		document.Query.URL = results[0].QueryURL
+	}
+
+	for _, result := range results {
+		document.Results = append(document.Results, newJSONResult(result))
+	}
+
+	encoder := p.newJSONEncoder()
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(document); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	return nil
+}
+
+// printNDJSON writes one compact JSON object per result per line, each
+// carrying the query URL and, in batch mode, the input term, so that
+// results can be streamed.
+func (p *Printer) printNDJSON(term string, results []datamuseapi.APIResponse) error {
+	encoder := p.newJSONEncoder()
+
+	for _, result := range results {
+		record := newJSONResult(result)
+		record.Term = term
+		record.QueryURL = result.QueryURL
+
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+	}
+
+	return nil
+}
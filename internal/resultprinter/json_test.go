@@ -0,0 +1,89 @@
+package resultprinter_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleResults returns enriched results as parsed by datamuseapi.
+func sampleResults() []datamuseapi.APIResponse {
+	queryURL := "https://api.datamuse.com/words?ml=ocean&md=dfprs&max=2"
+
+	return []datamuseapi.APIResponse{
+		{
+			Word:          "sea",
+			Score:         1001,
+			NumSyllables:  1,
+			Tags:          []string{"syn", "n"},
+			Definitions:   []string{"n\tA large body of salt water."},
+			Pronunciation: "S IY1 ",
+			Frequency:     120.5,
+			QueryURL:      queryURL,
+		},
+		{Word: "briny", Score: 998, NumSyllables: 2, QueryURL: queryURL},
+	}
+}
+
+func TestPrintResults_JSON(t *testing.T) {
+	t.Parallel()
+
+	output := printToString(t, sampleResults(),
+		resultprinter.DisplayOptions{Format: resultprinter.FormatJSON})
+
+	require.JSONEq(t, `{
+		"query": {"url": "https://api.datamuse.com/words?ml=ocean&md=dfprs&max=2", "count": 2},
+		"results": [
+			{
+				"word": "sea",
+				"score": 1001,
+				"numSyllables": 1,
+				"pronunciation": "S IY1",
+				"frequency": 120.5,
+				"tags": ["syn", "n"],
+				"definitions": ["n\tA large body of salt water."]
+			},
+			{"word": "briny", "score": 998, "numSyllables": 2}
+		]
+	}`, output)
+}
+
+func TestPrintResults_JSONEmpty(t *testing.T) {
+	t.Parallel()
+
+	output := printToString(t, nil,
+		resultprinter.DisplayOptions{Format: resultprinter.FormatJSON})
+
+	require.JSONEq(t, `{"query": {"count": 0}, "results": []}`, output)
+}
+
+func TestPrintResults_NDJSON(t *testing.T) {
+	t.Parallel()
+
+	output := printToString(t, sampleResults(),
+		resultprinter.DisplayOptions{Format: resultprinter.FormatNDJSON})
+
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 2)
+
+	var record map[string]any
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	require.Equal(t, "briny", record["word"])
+	require.Equal(t, "https://api.datamuse.com/words?ml=ocean&md=dfprs&max=2", record["queryURL"])
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := resultprinter.ParseFormat("NDJSON")
+	require.NoError(t, err)
+	require.Equal(t, resultprinter.FormatNDJSON, format)
+
+	_, err = resultprinter.ParseFormat("xml")
+	require.ErrorIs(t, err, resultprinter.ErrInvalidFormat)
+}
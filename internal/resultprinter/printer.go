@@ -0,0 +1,136 @@
+package resultprinter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
+)
+
+// NoResultsMessage is the notice written to the diagnostic output when a
+// human-oriented format has no results to show.
+const NoResultsMessage = "The search returned no results."
+
+// Printer formats query results according to DisplayOptions and writes
+// them to an io.Writer. Notices that are not part of the results, such as
+// an empty result set in a human-oriented format, go to a separate
+// diagnostic writer so that they never mix with the output.
+type Printer struct {
+	out     *errWriter
+	errOut  io.Writer
+	options DisplayOptions
+}
+
+// NewPrinter returns a Printer that writes results to out and
+// diagnostics to errOut. A nil errOut discards diagnostics.
+func NewPrinter(out, errOut io.Writer, options DisplayOptions) *Printer {
+	if errOut == nil {
+		errOut = io.Discard
+	}
+
+	return &Printer{out: &errWriter{w: out}, errOut: errOut, options: options}
+}
+
+// Print writes the results in the configured format. In text format it
+// prints the API query URL and result count if those options are enabled,
+// followed by the detailed results for each response. The JSON formats
+// always include every available field and the query metadata, while
+// CSV, TSV and table formats include a column for each enabled field.
+// A Template, when set, renders each result instead of the format.
+// Pronunciations are written in the configured PronNotation.
+func (p *Printer) Print(results []datamuseapi.APIResponse) error {
+	if len(results) < 1 && !p.Structured() {
+		fmt.Fprintln(p.errOut, NoResultsMessage)
+
+		return nil
+	}
+
+	if err := p.print(p.convertPronunciations(results)); err != nil {
+		return err
+	}
+
+	if p.out.err != nil {
+		return fmt.Errorf("failed to write output: %w", p.out.err)
+	}
+
+	return nil
+}
+
+// convertPronunciations returns the results with their pronunciations
+// in the configured notation, leaving the caller's results unchanged. In
+// IPA, the pronunciation given by Datamuse is preferred to a conversion.
+func (p *Printer) convertPronunciations(results []datamuseapi.APIResponse) []datamuseapi.APIResponse {
+	notation := p.options.PronNotation
+	if notation == "" || notation == phonetics.NotationARPAbet {
+		return results
+	}
+
+	converted := make([]datamuseapi.APIResponse, len(results))
+	for i, result := range results {
+		if notation == phonetics.NotationIPA && result.IPAPronunciation != "" {
+			result.Pronunciation = result.IPAPronunciation
+		} else {
+			result.Pronunciation = phonetics.Convert(result.Pronunciation, notation)
+		}
+
+		converted[i] = result
+	}
+
+	return converted
+}
+
+// print dispatches to the printer for the configured format.
+func (p *Printer) print(results []datamuseapi.APIResponse) error {
+	if p.options.Template != nil {
+		return p.printTemplate(results)
+	}
+
+	switch p.options.Format {
+	case FormatJSON:
+		return p.printJSON(results)
+	case FormatNDJSON:
+		return p.printNDJSON("", results)
+	case FormatCSV:
+		return p.printCSV(results)
+	case FormatTSV:
+		p.printTSV(results)
+	case FormatTable:
+		p.printTable(results)
+	case "", FormatText:
+		p.printText(results)
+	default:
+		return fmt.Errorf("%w %q", ErrInvalidFormat, p.options.Format)
+	}
+
+	return nil
+}
+
+// Structured reports whether the printer produces machine-readable
+// output, which is the case for the structured formats and templates.
+// Such output is never mixed with notices, and an empty result set is
+// printed as such.
+func (p *Printer) Structured() bool {
+	return p.options.Format.Structured() || p.options.Template != nil
+}
+
+// errWriter wraps an io.Writer and remembers the first write error, so
+// that the text printers can write freely and report failure once.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+// Write writes to the underlying writer unless an earlier write failed.
+func (ew *errWriter) Write(b []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+
+	n, err := ew.w.Write(b)
+	if err != nil {
+		ew.err = err
+	}
+
+	return n, err //nolint:wrapcheck
+}
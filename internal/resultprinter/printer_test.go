@@ -0,0 +1,124 @@
+package resultprinter_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+// printToString returns the output of a Printer for results.
+func printToString(t *testing.T, results []datamuseapi.APIResponse, options resultprinter.DisplayOptions) string {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	require.NoError(t, resultprinter.NewPrinter(&out, nil, options).Print(results))
+
+	return out.String()
+}
+
+func TestPrinter_Text(t *testing.T) {
+	t.Parallel()
+
+	output := printToString(t, sampleResults(), resultprinter.DisplayOptions{
+		ShowCountFlag: true,
+		ShowScore:     true,
+		ShowPOS:       true,
+	})
+
+	require.Equal(t, "Number of Results: 2\n"+
+		"sea\n\tScore: 1001\n\tPart of Speech: syn\n\tPart of Speech: n\n\n"+
+		"briny\n\tScore: 998\n\n", output)
+}
+
+// TestPrinter_PronNotation verifies that pronunciations are converted
+// for output without modifying the caller's results.
+func TestPrinter_PronNotation(t *testing.T) {
+	t.Parallel()
+
+	results := []datamuseapi.APIResponse{{Word: "away", Pronunciation: "AH0 W EY1 "}}
+
+	output := printToString(t, results, resultprinter.DisplayOptions{
+		ShowPronunciation: true,
+		PronNotation:      phonetics.NotationIPA,
+	})
+	require.Equal(t, "away\n\tPronunciation: əˈweɪ\n\n", output)
+
+	output = printToString(t, results, resultprinter.DisplayOptions{
+		ShowPronunciation: true,
+		PronNotation:      phonetics.NotationRespell,
+		Format:            resultprinter.FormatCSV,
+	})
+	require.Equal(t, "word,pronunciation\naway,uh-WAY\n", output)
+	require.Equal(t, "AH0 W EY1 ", results[0].Pronunciation)
+}
+
+// TestPrinter_PronNotation_DatamuseIPA verifies that the IPA given by
+// Datamuse is shown in IPA notation and that other notations are still
+// converted from ARPAbet.
+func TestPrinter_PronNotation_DatamuseIPA(t *testing.T) {
+	t.Parallel()
+
+	results := []datamuseapi.APIResponse{{Word: "away", Pronunciation: "AH0 W EY1 ", IPAPronunciation: "əwˈeɪ"}}
+
+	output := printToString(t, results, resultprinter.DisplayOptions{
+		ShowPronunciation: true,
+		PronNotation:      phonetics.NotationIPA,
+	})
+	require.Equal(t, "away\n\tPronunciation: əwˈeɪ\n\n", output)
+
+	output = printToString(t, results, resultprinter.DisplayOptions{
+		ShowPronunciation: true,
+		PronNotation:      phonetics.NotationRespell,
+	})
+	require.Equal(t, "away\n\tPronunciation: uh-WAY\n\n", output)
+
+	output = printToString(t, results, resultprinter.DisplayOptions{Format: resultprinter.FormatNDJSON})
+	require.JSONEq(t, `{"word":"away","pronunciation":"AH0 W EY1","ipaPronunciation":"əwˈeɪ"}`, output)
+}
+
+// TestPrinter_NoResults verifies that the notice for an empty result set
+// goes to the diagnostic writer in text format and that structured
+// formats print an empty result set instead.
+func TestPrinter_NoResults(t *testing.T) {
+	t.Parallel()
+
+	var out, errOut bytes.Buffer
+
+	require.NoError(t, resultprinter.NewPrinter(&out, &errOut, resultprinter.DisplayOptions{}).Print(nil))
+	require.Empty(t, out.String())
+	require.Equal(t, resultprinter.NoResultsMessage+"\n", errOut.String())
+
+	out.Reset()
+	errOut.Reset()
+
+	csvOptions := resultprinter.DisplayOptions{Format: resultprinter.FormatCSV}
+	require.NoError(t, resultprinter.NewPrinter(&out, &errOut, csvOptions).Print(nil))
+	require.Equal(t, "word\n", out.String())
+	require.Empty(t, errOut.String())
+}
+
+// failingWriter is an io.Writer whose writes always fail.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWriteTest
+}
+
+var errWriteTest = errors.New("disk full")
+
+func TestPrinter_WriteError(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []resultprinter.Format{resultprinter.FormatText, resultprinter.FormatJSON,
+		resultprinter.FormatTSV, resultprinter.FormatTable} {
+		printer := resultprinter.NewPrinter(failingWriter{}, nil, resultprinter.DisplayOptions{Format: format})
+
+		require.ErrorIs(t, printer.Print(sampleResults()), errWriteTest, format)
+	}
+}
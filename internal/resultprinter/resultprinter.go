@@ -4,9 +4,12 @@ package resultprinter
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"text/template"
 
 	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
 )
 
 // DisplayOptions contains flags to control which parts of the query
@@ -21,11 +24,16 @@ type DisplayOptions struct {
 	ShowQueryURL      bool
 	ShowScore         bool
 	ShowSyllables     bool
+	Format            Format
+	PronNotation      phonetics.Notation // Notation for pronunciations; ARPAbet when empty.
+	ListSeparator     string             // Joins tags and definitions in CSV and TSV output.
+	Template          *template.Template // Renders each result, overriding Format.
 }
 
 // ToMetadataString generates a metadata string for the query by appending
 // relevant abbreviations based on the flags set in DisplayOptions. If a
-// flag is enabled, the corresponding abbreviation is added to the metadata.
+// flag is enabled, the corresponding abbreviation is added to the metadata,
+// as are the abbreviations needed by the fields of the format template.
 func (opts *DisplayOptions) ToMetadataString(existingMd string) string {
 	if strings.ToLower(existingMd) == "none" {
 		existingMd = ""
@@ -36,7 +44,7 @@ func (opts *DisplayOptions) ToMetadataString(existingMd string) string {
 	builder.WriteString(existingMd)
 
 	addToMd := func(abbreviation string) {
-		if !strings.Contains(existingMd, abbreviation) {
+		if !strings.Contains(builder.String(), abbreviation) {
 			builder.WriteString(abbreviation)
 		}
 	}
@@ -61,49 +69,55 @@ func (opts *DisplayOptions) ToMetadataString(existingMd string) string {
 		addToMd("s")
 	}
 
+	if opts.Template != nil {
+		for _, letter := range TemplateMetadata(opts.Template) {
+			addToMd(string(letter))
+		}
+	}
+
 	return builder.String()
 }
 
 // printIntIfNotZero prints a labeled integer value
 // if it is greater than zero.
-func printIntIfNotZero(label string, value int) {
+func (p *Printer) printIntIfNotZero(label string, value int) {
 	if value > 0 {
-		fmt.Printf("\t%s: %d\n", label, value)
+		fmt.Fprintf(p.out, "\t%s: %d\n", label, value)
 	}
 }
 
 // printFloatIfNotZero prints a labeled float value
 // if it is greater than zero.
-func printFloatIfNotZero(label string, value float64) {
+func (p *Printer) printFloatIfNotZero(label string, value float64) {
 	if value > 0 {
-		fmt.Printf("\t%s: %.6f\n", label, value)
+		fmt.Fprintf(p.out, "\t%s: %.6f\n", label, value)
 	}
 }
 
 // printStringIfNotEmpty prints a labeled string value
 // if it is non-empty.
-func printStringIfNotEmpty(label, value string) {
+func (p *Printer) printStringIfNotEmpty(label, value string) {
 	if value != "" {
-		fmt.Printf("\t%s: %s\n", label, value)
+		fmt.Fprintf(p.out, "\t%s: %s\n", label, value)
 	}
 }
 
 // printDefinitions prints the definitions of the query result if
 // the show flag is set and definitions are available.
-func printDefinitions(result datamuseapi.APIResponse, show bool) {
+func (p *Printer) printDefinitions(result datamuseapi.APIResponse, show bool) {
 	if show && len(result.Definitions) > 0 {
 		for _, def := range result.Definitions {
-			fmt.Printf("\tDefinition: %s\n", def)
+			fmt.Fprintf(p.out, "\tDefinition: %s\n", def)
 		}
 	}
 }
 
 // printPartOfSpeech prints the parts of speech (POS) for the query result
 // if the ShowPOS flag is enabled.
-func printPartOfSpeech(result datamuseapi.APIResponse, show bool) {
+func (p *Printer) printPartOfSpeech(result datamuseapi.APIResponse, show bool) {
 	if show && len(result.Tags) > 0 {
 		for _, tag := range result.Tags {
-			fmt.Printf("\tPart of Speech: %s\n", tag)
+			fmt.Fprintf(p.out, "\tPart of Speech: %s\n", tag)
 		}
 	}
 }
@@ -111,48 +125,60 @@ func printPartOfSpeech(result datamuseapi.APIResponse, show bool) {
 // printResultDetails prints the details of a single APIResponse,
 // including score, syllables, pronunciation, etc., based on the
 // flags set in DisplayOptions.
-func printResultDetails(result datamuseapi.APIResponse, options DisplayOptions) {
-	fmt.Printf("%s\n", result.Word)
+func (p *Printer) printResultDetails(result datamuseapi.APIResponse) {
+	options := p.options
+
+	fmt.Fprintf(p.out, "%s\n", result.Word)
 
 	if options.ShowScore {
-		printIntIfNotZero("Score", result.Score)
+		p.printIntIfNotZero("Score", result.Score)
 	}
 
 	if options.ShowSyllables {
-		printIntIfNotZero("Num Syllables", result.NumSyllables)
+		p.printIntIfNotZero("Num Syllables", result.NumSyllables)
 	}
 
 	if options.ShowPronunciation {
-		printStringIfNotEmpty("Pronunciation", result.Pronunciation)
+		p.printStringIfNotEmpty("Pronunciation", result.Pronunciation)
 	}
 
 	if options.ShowFrequency {
-		printFloatIfNotZero("Frequency", result.Frequency)
+		p.printFloatIfNotZero("Frequency", result.Frequency)
 	}
 
-	printPartOfSpeech(result, options.ShowPOS)
-	printDefinitions(result, options.ShowDefinitions)
+	p.printPartOfSpeech(result, options.ShowPOS)
+	p.printDefinitions(result, options.ShowDefinitions)
 
-	fmt.Println() // Line break between results.
+	fmt.Fprintln(p.out) // Line break between results.
 }
 
-// PrintResults processes and displays a list of APIResponse objects
-// according to the flags set in DisplayOptions. It prints the API query
-// URL and result count if those options are enabled, followed by the
-// detailed results for each response.
-func PrintResults(results []datamuseapi.APIResponse, options DisplayOptions) {
-	// Display the API query URL if the ShowQueryURL flag is enabled.
-	if options.ShowQueryURL && len(results) > 0 {
-		fmt.Printf("Datamuse API URL: %s\n", results[0].QueryURL)
-	}
+// PrintResults processes and displays a list of APIResponse objects on
+// standard output according to the flags set in DisplayOptions. It is
+// shorthand for a Printer writing to os.Stdout and os.Stderr.
+func PrintResults(results []datamuseapi.APIResponse, options DisplayOptions) error {
+	return NewPrinter(os.Stdout, os.Stderr, options).Print(results)
+}
 
-	// Display the count of results if the ShowCountFlag is enabled.
-	if options.ShowCountFlag {
-		fmt.Printf("Number of Results: %d\n", len(results))
-	}
+// printText displays results in the indented, human-oriented format.
+func (p *Printer) printText(results []datamuseapi.APIResponse) {
+	p.printSummary(results)
 
 	// Display each result based on the options provided.
 	for _, result := range results {
-		printResultDetails(result, options)
+		p.printResultDetails(result)
+	}
+}
+
+// printSummary prints the query URL and result count lines of the
+// human-oriented formats when those options are enabled.
+func (p *Printer) printSummary(results []datamuseapi.APIResponse) {
+	// Display the API query URL if the ShowQueryURL flag is enabled.
+	if p.options.ShowQueryURL && len(results) > 0 {
+		fmt.Fprintf(p.out, "Datamuse API URL: %s\n", results[0].QueryURL)
+	}
+
+	// Display the count of results if the ShowCountFlag is enabled.
+	if p.options.ShowCountFlag {
+		fmt.Fprintf(p.out, "Number of Results: %d\n", len(results))
 	}
 }
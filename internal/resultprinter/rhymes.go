@@ -0,0 +1,90 @@
+package resultprinter
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// rhymeIndent indents the word lists of the rhyme layout.
+const rhymeIndent = "  "
+
+// PrintRhymes writes the perfect and near rhymes of word. In text format
+// they are laid out like a rhyming dictionary: perfect rhymes first, then
+// near rhymes, each grouped by syllable count with the words of a group
+// wrapped to the output width. Other formats print the perfect rhymes
+// followed by the near rhymes as an ordinary result list.
+func (p *Printer) PrintRhymes(word string, perfect, near []datamuseapi.APIResponse) error {
+	if p.options.Template != nil || (p.options.Format != "" && p.options.Format != FormatText) {
+		return p.Print(slices.Concat(perfect, near))
+	}
+
+	if len(perfect) == 0 && len(near) == 0 {
+		return p.Print(nil)
+	}
+
+	p.printSummary(slices.Concat(perfect, near))
+
+	width := detectTableLayout(p.out.w).width
+	p.printRhymeSection(fmt.Sprintf("Perfect rhymes for %q", word), perfect, width)
+	fmt.Fprintln(p.out)
+	p.printRhymeSection(fmt.Sprintf("Near rhymes for %q", word), near, width)
+
+	if p.out.err != nil {
+		return fmt.Errorf("failed to write output: %w", p.out.err)
+	}
+
+	return nil
+}
+
+// printRhymeSection prints a heading and the rhymes grouped by syllable
+// count, fewest syllables first. Words of unknown length come last.
+func (p *Printer) printRhymeSection(heading string, rhymes []datamuseapi.APIResponse, width int) {
+	fmt.Fprintln(p.out, heading)
+
+	if len(rhymes) == 0 {
+		fmt.Fprintln(p.out, rhymeIndent+"(none)")
+
+		return
+	}
+
+	groups := make(map[int][]string)
+	for _, rhyme := range rhymes {
+		groups[rhyme.NumSyllables] = append(groups[rhyme.NumSyllables], rhyme.Word)
+	}
+
+	counts := make([]int, 0, len(groups))
+	for count := range groups {
+		counts = append(counts, count)
+	}
+
+	slices.SortFunc(counts, func(a, b int) int {
+		// Unknown (zero) syllable counts sort last.
+		if (a == 0) != (b == 0) {
+			return cmp.Compare(b, a)
+		}
+
+		return cmp.Compare(a, b)
+	})
+
+	for _, count := range counts {
+		label := "Other"
+
+		switch {
+		case count == 1:
+			label = "1 syllable"
+		case count > 1:
+			label = fmt.Sprintf("%d syllables", count)
+		}
+
+		fmt.Fprintf(p.out, "\n%s%s:\n", rhymeIndent, label)
+
+		text := strings.Join(groups[count], ", ")
+		for _, line := range wrapText(text, max(width-2*len(rhymeIndent), minLastColumnWidth)) {
+			fmt.Fprintln(p.out, rhymeIndent+rhymeIndent+line)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package resultprinter_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+//nolint:paralleltest
+func TestPrinter_PrintRhymes(t *testing.T) {
+	t.Setenv("COLUMNS", "24")
+
+	perfect := []datamuseapi.APIResponse{
+		{Word: "away", NumSyllables: 2},
+		{Word: "way", NumSyllables: 1},
+		{Word: "say", NumSyllables: 1},
+		{Word: "today", NumSyllables: 2},
+		{Word: "may", NumSyllables: 1},
+		{Word: "lay", NumSyllables: 1},
+		{Word: "doré", NumSyllables: 0},
+	}
+	near := []datamuseapi.APIResponse{{Word: "tape", NumSyllables: 1}}
+
+	var out bytes.Buffer
+
+	require.NoError(t, resultprinter.NewPrinter(&out, nil, resultprinter.DisplayOptions{}).
+		PrintRhymes("day", perfect, near))
+
+	require.Equal(t, `Perfect rhymes for "day"
+
+  1 syllable:
+    way, say, may, lay
+
+  2 syllables:
+    away, today
+
+  Other:
+    doré
+
+Near rhymes for "day"
+
+  1 syllable:
+    tape
+`, out.String())
+}
+
+func TestPrinter_PrintRhymesJSON(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	printer := resultprinter.NewPrinter(&out, nil, resultprinter.DisplayOptions{Format: resultprinter.FormatNDJSON})
+
+	require.NoError(t, printer.PrintRhymes("day",
+		[]datamuseapi.APIResponse{{Word: "way"}}, []datamuseapi.APIResponse{{Word: "tape"}}))
+	require.Equal(t, "{\"word\":\"way\"}\n{\"word\":\"tape\"}\n", out.String())
+}
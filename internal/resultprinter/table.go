@@ -0,0 +1,219 @@
+package resultprinter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"golang.org/x/term"
+)
+
+const (
+	// defaultTableWidth is the table width used when the output is not a
+	// terminal and $COLUMNS is not set.
+	defaultTableWidth = 80
+	// minLastColumnWidth is the narrowest the last column is made,
+	// even if the table then exceeds the available width.
+	minLastColumnWidth = 10
+	// columnGap separates adjacent table columns.
+	columnGap = "  "
+	// ellipsis marks a cell truncated to fit the table width.
+	ellipsis = "…"
+	// cellSeparator joins the tags of a result within a table cell.
+	cellSeparator = ", "
+)
+
+// tableLayout describes how wide a table may be and whether the last
+// column wraps onto further lines or is truncated.
+type tableLayout struct {
+	width int
+	wrap  bool
+}
+
+// detectTableLayout sizes the table to the terminal when out is one,
+// wrapping long cells. Otherwise, such as when the output is piped, the
+// width is taken from $COLUMNS (default 80) and long cells are truncated
+// so that every result stays on one line.
+func detectTableLayout(out io.Writer) tableLayout {
+	if file, ok := out.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		if width, _, err := term.GetSize(int(file.Fd())); err == nil && width > 0 {
+			return tableLayout{width: width, wrap: true}
+		}
+	}
+
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && width > 0 {
+		return tableLayout{width: width}
+	}
+
+	return tableLayout{width: defaultTableWidth}
+}
+
+// printTable writes one row per result with aligned columns for the
+// enabled fields. The last column, usually the definitions, takes the
+// remaining width.
+func (p *Printer) printTable(results []datamuseapi.APIResponse) {
+	p.printSummary(results)
+
+	layout := detectTableLayout(p.out.w)
+	columns := p.options.columns()
+	last := len(columns) - 1
+
+	// Each definition starts a new line when the last column wraps.
+	lastSeparator := DefaultListSeparator
+	if layout.wrap {
+		lastSeparator = "\n"
+	}
+
+	header := make([]string, len(columns))
+	rows := make([][]string, len(results))
+
+	for i, col := range columns {
+		header[i] = strings.ToUpper(col.header)
+	}
+
+	for r, result := range results {
+		rows[r] = make([]string, len(columns))
+
+		for i, col := range columns {
+			separator := cellSeparator
+			if i == last {
+				separator = lastSeparator
+			}
+
+			rows[r][i] = col.value(result, separator)
+		}
+	}
+
+	widths := columnWidths(header, rows, layout.width)
+
+	p.writeTableRow(header, columns, widths, layout)
+
+	for _, row := range rows {
+		p.writeTableRow(row, columns, widths, layout)
+	}
+}
+
+// columnWidths returns the width of each column: the widest cell of
+// every column but the last, which gets the rest of the table width.
+func columnWidths(header []string, rows [][]string, tableWidth int) []int {
+	widths := make([]int, len(header))
+
+	for i, cell := range header {
+		widths[i] = utf8.RuneCountInString(cell)
+	}
+
+	for _, row := range rows {
+		for i, cell := range row {
+			for _, line := range strings.Split(cell, "\n") {
+				widths[i] = max(widths[i], utf8.RuneCountInString(line))
+			}
+		}
+	}
+
+	last := len(widths) - 1
+	used := len(columnGap) * last
+
+	for _, width := range widths[:last] {
+		used += width
+	}
+
+	widths[last] = min(widths[last], max(tableWidth-used, minLastColumnWidth))
+
+	return widths
+}
+
+// writeTableRow prints a row, continuing a wrapped last cell on further
+// lines with the other columns left blank.
+func (p *Printer) writeTableRow(row []string, columns []column, widths []int, layout tableLayout) {
+	last := len(row) - 1
+	lines := fitCell(row[last], widths[last], layout.wrap)
+
+	for n, line := range lines {
+		var builder strings.Builder
+
+		for i := range last {
+			cell := ""
+			if n == 0 {
+				cell = row[i]
+			}
+
+			builder.WriteString(padCell(cell, widths[i], columns[i].numeric))
+			builder.WriteString(columnGap)
+		}
+
+		builder.WriteString(line)
+		fmt.Fprintln(p.out, strings.TrimRight(builder.String(), " "))
+	}
+}
+
+// fitCell returns the lines of a cell fitted to width, either wrapped at
+// word boundaries or truncated with an ellipsis.
+func fitCell(cell string, width int, wrap bool) []string {
+	if !wrap {
+		return []string{truncateCell(cell, width)}
+	}
+
+	var lines []string
+
+	for _, paragraph := range strings.Split(cell, "\n") {
+		lines = append(lines, wrapText(paragraph, width)...)
+	}
+
+	return lines
+}
+
+// truncateCell shortens s to width runes, ending it with an ellipsis
+// when anything is cut.
+func truncateCell(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+
+	return strings.TrimRight(string(runes[:width-1]), " ") + ellipsis
+}
+
+// wrapText breaks s into lines of at most width runes at spaces. Words
+// longer than width are truncated.
+func wrapText(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var (
+		lines []string
+		line  string
+	)
+
+	for _, word := range words {
+		word = truncateCell(word, width)
+
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+
+	return append(lines, line)
+}
+
+// padCell pads s with spaces to width, aligning numbers to the right.
+func padCell(s string, width int, alignRight bool) string {
+	padding := strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+	if alignRight {
+		return padding + s
+	}
+
+	return s + padding
+}
@@ -0,0 +1,49 @@
+package resultprinter_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPrintResults_Table verifies that piped table output aligns columns
+// and truncates the last column to $COLUMNS.
+//
+//nolint:paralleltest
+func TestPrintResults_Table(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+
+	results := []datamuseapi.APIResponse{
+		{Word: "sea", Score: 1001, NumSyllables: 1, Definitions: []string{"n\tA large body of salt water."}},
+		{Word: "high-seas", Score: 700, NumSyllables: 2, Definitions: []string{"n\tThe open sea."}},
+	}
+
+	output := printToString(t, results, resultprinter.DisplayOptions{
+		Format:          resultprinter.FormatTable,
+		ShowScore:       true,
+		ShowSyllables:   true,
+		ShowDefinitions: true,
+		ShowCountFlag:   true,
+	})
+
+	require.Equal(t, "Number of Results: 2\n"+
+		"WORD       SCORE  SYLLABLES  DEFINITIONS\n"+
+		"sea         1001          1  (n) A larg…\n"+
+		"high-seas    700          2  (n) The op…\n", output)
+}
+
+// TestPrintResults_TableWordOnly verifies that a table without optional
+// columns lists the words.
+//
+//nolint:paralleltest
+func TestPrintResults_TableWordOnly(t *testing.T) {
+	t.Setenv("COLUMNS", "80")
+
+	output := printToString(t, sampleResults(), resultprinter.DisplayOptions{
+		Format: resultprinter.FormatTable,
+	})
+
+	require.Equal(t, "WORD\nsea\nbriny\n", output)
+}
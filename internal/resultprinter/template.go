@@ -0,0 +1,177 @@
+package resultprinter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// ErrInvalidTemplate reports a format template that cannot be parsed.
+var ErrInvalidTemplate = errors.New("invalid format template")
+
+// templateFuncs are the helper functions available to format templates
+// in addition to the text/template builtins.
+//
+//nolint:gochecknoglobals
+var templateFuncs = template.FuncMap{
+	// join joins a list with a separator: {{join ", " .Tags}}.
+	"join": func(separator string, items []string) string {
+		return strings.Join(items, separator)
+	},
+	// tags joins parts of speech with commas: {{tags .Tags}}.
+	"tags": func(tags []string) string {
+		return strings.Join(tags, ", ")
+	},
+	// freq formats a frequency with two decimals: {{freq .Frequency}}.
+	"freq": func(frequency float64) string {
+		return strconv.FormatFloat(frequency, 'f', 2, 64)
+	},
+	// syllables spells out a syllable count: {{syllables .NumSyllables}}.
+	"syllables": func(count int) string {
+		if count == 1 {
+			return "1 syllable"
+		}
+
+		return strconv.Itoa(count) + " syllables"
+	},
+	// def formats a definition as "(pos) text": {{def (index .Definitions 0)}}.
+	"def": formatDefinition,
+	// trim removes surrounding whitespace: {{trim .Pronunciation}}.
+	"trim": strings.TrimSpace,
+}
+
+// fieldMetadata maps the result fields filled in from metadata to the
+// metadata letter that asks Datamuse for them.
+//
+//nolint:gochecknoglobals
+var fieldMetadata = map[string]string{
+	"Definitions":      "d",
+	"Frequency":        "f",
+	"Tags":             "p",
+	"Pronunciation":    "r",
+	"IPAPronunciation": "r",
+	"NumSyllables":     "s",
+}
+
+// TemplateMetadata returns the metadata letters needed by the result
+// fields that tmpl refers to, such as "d" for {{.Definitions}}, so that
+// a template does not also need the matching --metadata flag.
+func TemplateMetadata(tmpl *template.Template) string {
+	var letters strings.Builder
+
+	addField := func(name string) {
+		if letter := fieldMetadata[name]; letter != "" && !strings.Contains(letters.String(), letter) {
+			letters.WriteString(letter)
+		}
+	}
+
+	for _, defined := range tmpl.Templates() {
+		if defined.Tree != nil {
+			walkFields(defined.Tree.Root, addField)
+		}
+	}
+
+	return letters.String()
+}
+
+// walkFields calls addField with each field name used in the template
+// node, such as "Definitions" for .Definitions or $.Definitions.
+func walkFields(node parse.Node, addField func(string)) {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return
+		}
+
+		for _, child := range node.Nodes {
+			walkFields(child, addField)
+		}
+	case *parse.ActionNode:
+		walkFields(node.Pipe, addField)
+	case *parse.PipeNode:
+		if node == nil {
+			return
+		}
+
+		for _, command := range node.Cmds {
+			walkFields(command, addField)
+		}
+	case *parse.CommandNode:
+		for _, arg := range node.Args {
+			walkFields(arg, addField)
+		}
+	case *parse.FieldNode:
+		for _, name := range node.Ident {
+			addField(name)
+		}
+	case *parse.VariableNode:
+		for _, name := range node.Ident[1:] {
+			addField(name)
+		}
+	case *parse.ChainNode:
+		walkFields(node.Node, addField)
+
+		for _, name := range node.Field {
+			addField(name)
+		}
+	case *parse.IfNode:
+		walkBranch(&node.BranchNode, addField)
+	case *parse.RangeNode:
+		walkBranch(&node.BranchNode, addField)
+	case *parse.WithNode:
+		walkBranch(&node.BranchNode, addField)
+	case *parse.TemplateNode:
+		walkFields(node.Pipe, addField)
+	}
+}
+
+// walkBranch walks the pipeline and both lists of an if, range or with
+// action.
+func walkBranch(node *parse.BranchNode, addField func(string)) {
+	walkFields(node.Pipe, addField)
+	walkFields(node.List, addField)
+	walkFields(node.ElseList, addField)
+}
+
+// ParseTemplate parses a text/template used to render each result. The
+// template is executed with a datamuseapi.APIResponse, so it can refer
+// to fields such as {{.Word}}, {{.Score}} and {{.Definitions}}, and may
+// use the helpers join, tags, freq, syllables, def and trim. Use
+// TemplateMetadata to find the metadata its fields need.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return tmpl, nil
+}
+
+// printTemplate renders each result with the template, ending each one
+// with a newline unless the template already produced one.
+func (p *Printer) printTemplate(results []datamuseapi.APIResponse) error {
+	var buf bytes.Buffer
+
+	for _, result := range results {
+		buf.Reset()
+
+		if err := p.options.Template.Execute(&buf, result); err != nil {
+			return fmt.Errorf("failed to render %q: %w", result.Word, err)
+		}
+
+		if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteByte('\n')
+		}
+
+		// A write error is remembered by p.out and reported by Print.
+		_, _ = p.out.Write(buf.Bytes())
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package resultprinter_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/resultprinter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintResults_Template(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := resultprinter.ParseTemplate(
+		`{{.Word}} ({{syllables .NumSyllables}}, {{freq .Frequency}}) {{tags .Tags}} | {{join "/" .Definitions | trim}}`)
+	require.NoError(t, err)
+
+	output := printToString(t, sampleResults(), resultprinter.DisplayOptions{Template: tmpl})
+
+	require.Equal(t, "sea (1 syllable, 120.50) syn, n | n\tA large body of salt water.\n"+
+		"briny (2 syllables, 0.00)  | \n", output)
+}
+
+func TestPrintResults_TemplateNewline(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := resultprinter.ParseTemplate("{{.Word}}{{range .Definitions}}\n  {{def .}}{{end}}\n")
+	require.NoError(t, err)
+
+	output := printToString(t, sampleResults(), resultprinter.DisplayOptions{Template: tmpl})
+
+	require.Equal(t, "sea\n  (n) A large body of salt water.\nbriny\n", output)
+}
+
+func TestParseTemplate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := resultprinter.ParseTemplate("{{.Word")
+
+	require.ErrorIs(t, err, resultprinter.ErrInvalidTemplate)
+}
+
+func TestTemplateMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		`{{.Word}} {{.Score}}`:                                             "",
+		`{{.Word}}: {{range .Definitions}}{{def .}}; {{end}}`:              "d",
+		`{{if gt .Frequency 1.0}}{{trim .Pronunciation}}{{end}}`:           "fr",
+		`{{with $.Tags}}{{tags .}}{{else}}{{.NumSyllables}}{{end}}`:        "ps",
+		`{{define "ipa"}}{{.IPAPronunciation}}{{end}}{{template "ipa" .}}`: "r",
+	}
+
+	for text, expected := range tests {
+		tmpl, err := resultprinter.ParseTemplate(text)
+		require.NoError(t, err, text)
+		require.Equal(t, expected, resultprinter.TemplateMetadata(tmpl), text)
+	}
+
+	tmpl, err := resultprinter.ParseTemplate(`{{.Word}} {{freq .Frequency}}`)
+	require.NoError(t, err)
+
+	opts := resultprinter.DisplayOptions{ShowDefinitions: true, Template: tmpl}
+	require.Equal(t, "df", opts.ToMetadataString(""))
+	require.Equal(t, "fd", opts.ToMetadataString("f"))
+}
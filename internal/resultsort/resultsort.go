@@ -0,0 +1,165 @@
+// Package resultsort orders Datamuse results on the client by one or
+// more keys such as frequency or syllable count.
+package resultsort
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// Field is a result property that results can be sorted by.
+type Field string
+
+// Sort fields accepted by Parse.
+const (
+	FieldScore     Field = "score"     // Datamuse score
+	FieldFreq      Field = "freq"      // Word frequency (requires md=f)
+	FieldSyllables Field = "syllables" // Syllable count (requires md=s)
+	FieldAlpha     Field = "alpha"     // Word, case-insensitively
+	FieldLength    Field = "length"    // Number of characters in the word
+)
+
+// ErrInvalidSortKey reports a sort key with an unknown field.
+var ErrInvalidSortKey = errors.New("invalid sort key")
+
+// fields lists the supported fields in the order shown in help text.
+//
+//nolint:gochecknoglobals
+var fields = []Field{FieldScore, FieldFreq, FieldSyllables, FieldAlpha, FieldLength}
+
+// metadata maps the fields that are only filled in on request to the
+// Datamuse metadata letter that requests them.
+//
+//nolint:gochecknoglobals
+var metadata = map[Field]string{
+	FieldFreq:      "f",
+	FieldSyllables: "s",
+}
+
+// Key is a sort field and direction.
+type Key struct {
+	Field      Field
+	Descending bool
+}
+
+// String returns the key as accepted by Parse, e.g. "-freq".
+func (k Key) String() string {
+	if k.Descending {
+		return "-" + string(k.Field)
+	}
+
+	return string(k.Field)
+}
+
+// Fields returns the supported sort fields.
+func Fields() []Field {
+	return slices.Clone(fields)
+}
+
+// FieldNames returns the supported field names separated by ", ".
+func FieldNames() string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = string(field)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// ParseKey converts a key such as "freq" or "-freq" (descending) to a
+// Key.
+func ParseKey(value string) (Key, error) {
+	name, descending := strings.CutPrefix(strings.TrimSpace(value), "-")
+
+	for _, field := range fields {
+		if strings.EqualFold(name, string(field)) {
+			return Key{Field: field, Descending: descending}, nil
+		}
+	}
+
+	return Key{}, fmt.Errorf("%w %q: expected one of %s, optionally prefixed with - for descending order",
+		ErrInvalidSortKey, value, FieldNames())
+}
+
+// Parse converts sort keys in order of precedence, such as
+// ["-freq", "alpha"], to Keys. Later keys break ties between results
+// that compare equal on earlier ones.
+func Parse(values []string) ([]Key, error) {
+	keys := make([]Key, 0, len(values))
+
+	for _, value := range values {
+		key, err := ParseKey(value)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Metadata returns the Datamuse metadata letters (as used in the md
+// parameter) needed to sort by keys, such as "f" for a frequency sort.
+func Metadata(keys []Key) string {
+	var letters strings.Builder
+
+	for _, key := range keys {
+		if letter, ok := metadata[key.Field]; ok && !strings.Contains(letters.String(), letter) {
+			letters.WriteString(letter)
+		}
+	}
+
+	return letters.String()
+}
+
+// Sort orders results by keys. The sort is stable, so results that are
+// equal on every key keep the order returned by Datamuse.
+func Sort(results []datamuseapi.APIResponse, keys []Key) {
+	if len(keys) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(results, func(a, b datamuseapi.APIResponse) int {
+		for _, key := range keys {
+			order := compare(a, b, key.Field)
+			if key.Descending {
+				order = -order
+			}
+
+			if order != 0 {
+				return order
+			}
+		}
+
+		return 0
+	})
+}
+
+// compare compares two results in ascending order of field.
+func compare(a, b datamuseapi.APIResponse, field Field) int {
+	switch field {
+	case FieldScore:
+		return cmp.Compare(a.Score, b.Score)
+	case FieldFreq:
+		return cmp.Compare(a.Frequency, b.Frequency)
+	case FieldSyllables:
+		return cmp.Compare(a.NumSyllables, b.NumSyllables)
+	case FieldAlpha:
+		if order := cmp.Compare(strings.ToLower(a.Word), strings.ToLower(b.Word)); order != 0 {
+			return order
+		}
+
+		return cmp.Compare(a.Word, b.Word)
+	case FieldLength:
+		return cmp.Compare(utf8.RuneCountInString(a.Word), utf8.RuneCountInString(b.Word))
+	default:
+		return 0
+	}
+}
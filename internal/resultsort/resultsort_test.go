@@ -0,0 +1,86 @@
+package resultsort_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/resultsort"
+	"github.com/stretchr/testify/require"
+)
+
+// words returns the words of results in order.
+func words(results []datamuseapi.APIResponse) []string {
+	words := make([]string, len(results))
+	for i, result := range results {
+		words[i] = result.Word
+	}
+
+	return words
+}
+
+// sampleResults returns results in Datamuse score order.
+func sampleResults() []datamuseapi.APIResponse {
+	return []datamuseapi.APIResponse{
+		{Word: "sea", Score: 1001, NumSyllables: 1, Frequency: 120.5},
+		{Word: "briny", Score: 998, NumSyllables: 2, Frequency: 0.7},
+		{Word: "Deep", Score: 950, NumSyllables: 1, Frequency: 210.3},
+		{Word: "main", Score: 900, NumSyllables: 1, Frequency: 301.1},
+		{Word: "atlantic", Score: 850, NumSyllables: 3, Frequency: 30.2},
+	}
+}
+
+func TestSort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		keys     []string
+		expected []string
+	}{
+		{"no keys", nil, []string{"sea", "briny", "Deep", "main", "atlantic"}},
+		{"score ascending", []string{"score"}, []string{"atlantic", "main", "Deep", "briny", "sea"}},
+		{"freq descending", []string{"-freq"}, []string{"main", "Deep", "sea", "atlantic", "briny"}},
+		{"alpha ignores case", []string{"alpha"}, []string{"atlantic", "briny", "Deep", "main", "sea"}},
+		{"length", []string{"length"}, []string{"sea", "Deep", "main", "briny", "atlantic"}},
+		{"syllables then alpha", []string{"syllables", "alpha"}, []string{"Deep", "main", "sea", "briny", "atlantic"}},
+		{"syllables is stable", []string{"-syllables"}, []string{"atlantic", "briny", "sea", "Deep", "main"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys, err := resultsort.Parse(test.keys)
+			require.NoError(t, err)
+
+			results := sampleResults()
+			resultsort.Sort(results, keys)
+
+			require.Equal(t, test.expected, words(results))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	keys, err := resultsort.Parse([]string{"-FREQ", " alpha"})
+	require.NoError(t, err)
+	require.Equal(t, []resultsort.Key{
+		{Field: resultsort.FieldFreq, Descending: true},
+		{Field: resultsort.FieldAlpha},
+	}, keys)
+	require.Equal(t, "-freq", keys[0].String())
+
+	_, err = resultsort.Parse([]string{"rhyme"})
+	require.ErrorIs(t, err, resultsort.ErrInvalidSortKey)
+}
+
+func TestMetadata(t *testing.T) {
+	t.Parallel()
+
+	keys, err := resultsort.Parse([]string{"-freq", "alpha", "syllables", "freq"})
+	require.NoError(t, err)
+	require.Equal(t, "fs", resultsort.Metadata(keys))
+	require.Empty(t, resultsort.Metadata(nil))
+}
@@ -0,0 +1,17 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+)
+
+// RunLoop runs the browser loop for model on a screen whose size is
+// reported by size, as Run does on a terminal.
+func RunLoop(ctx context.Context, out io.Writer, size func() (int, int, error), model *Model, keys <-chan Key,
+	resized <-chan os.Signal, querier Querier, base datamuseapi.QueryParams,
+) error {
+	return loop(ctx, &screen{out: out, size: size, model: model}, keys, resized, querier, base)
+}
@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Key is a key press: a name such as KeyUp for special keys, or the
+// typed character.
+type Key string
+
+// Special keys reported by DecodeKeys.
+const (
+	KeyUp        Key = "up"
+	KeyDown      Key = "down"
+	KeyLeft      Key = "left"
+	KeyRight     Key = "right"
+	KeyPageUp    Key = "pgup"
+	KeyPageDown  Key = "pgdn"
+	KeyHome      Key = "home"
+	KeyEnd       Key = "end"
+	KeyEnter     Key = "enter"
+	KeyEscape    Key = "esc"
+	KeyBackspace Key = "backspace"
+	KeyCtrlC     Key = "ctrl-c"
+)
+
+// escapeSequences maps the escape sequences sent by common terminals to
+// keys.
+//
+//nolint:gochecknoglobals
+var escapeSequences = map[string]Key{
+	"\x1b[A": KeyUp, "\x1bOA": KeyUp,
+	"\x1b[B": KeyDown, "\x1bOB": KeyDown,
+	"\x1b[C": KeyRight, "\x1bOC": KeyRight,
+	"\x1b[D": KeyLeft, "\x1bOD": KeyLeft,
+	"\x1b[5~": KeyPageUp, "\x1b[6~": KeyPageDown,
+	"\x1b[H": KeyHome, "\x1bOH": KeyHome, "\x1b[1~": KeyHome, "\x1b[7~": KeyHome,
+	"\x1b[F": KeyEnd, "\x1bOF": KeyEnd, "\x1b[4~": KeyEnd, "\x1b[8~": KeyEnd,
+}
+
+// DecodeKeys converts the bytes read from a terminal in raw mode to key
+// presses. Unrecognized escape sequences are dropped, and an escape
+// character on its own is the Escape key.
+func DecodeKeys(input []byte) []Key {
+	var keys []Key
+
+	for len(input) > 0 {
+		switch input[0] {
+		case '\x1b':
+			n := escapeSequenceLength(input)
+			if n == 1 {
+				keys = append(keys, KeyEscape)
+			} else if key, ok := escapeSequences[string(input[:n])]; ok {
+				keys = append(keys, key)
+			}
+
+			input = input[n:]
+
+			continue
+		case '\r', '\n':
+			keys = append(keys, KeyEnter)
+		case '\x7f', '\b':
+			keys = append(keys, KeyBackspace)
+		case '\x03':
+			keys = append(keys, KeyCtrlC)
+		default:
+			r, n := utf8.DecodeRune(input)
+			if r >= ' ' {
+				keys = append(keys, Key(string(r)))
+			}
+
+			input = input[n:]
+
+			continue
+		}
+
+		input = input[1:]
+	}
+
+	return keys
+}
+
+// escapeSequenceLength returns the length of the escape sequence at the
+// start of input: a CSI sequence ("ESC [" up to a final byte from @ to
+// ~), an SS3 sequence ("ESC O" and one byte), or 1 for a lone escape.
+func escapeSequenceLength(input []byte) int {
+	if len(input) < 2 { //nolint:mnd
+		return 1
+	}
+
+	switch input[1] {
+	case 'O':
+		return min(3, len(input)) //nolint:mnd
+	case '[':
+		if i := strings.IndexFunc(string(input[2:]), func(r rune) bool { return r >= '@' && r <= '~' }); i >= 0 {
+			return i + 3 //nolint:mnd
+		}
+
+		return len(input)
+	}
+
+	return 1
+}
@@ -0,0 +1,19 @@
+package tui_test
+
+import (
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/tui"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	t.Parallel()
+
+	keys := tui.DecodeKeys([]byte("\x1b[Aj\x1bOB\x1b[6~\r\x7f\x1b\x03é\x1b[1;5C"))
+
+	require.Equal(t, []tui.Key{
+		tui.KeyUp, "j", tui.KeyDown, tui.KeyPageDown, tui.KeyEnter, tui.KeyBackspace,
+		tui.KeyEscape, tui.KeyCtrlC, "é",
+	}, keys)
+}
@@ -0,0 +1,510 @@
+// Package tui implements Polyhymnia's full-screen browser: a searchable
+// list of results beside the details of the highlighted word, with keys
+// that pivot the word into a new query and a breadcrumb trail leading
+// back to earlier queries. The Model holds the state and renders it
+// without touching the terminal, and Run drives it on one.
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/phonetics"
+)
+
+const (
+	// minListWidth and maxListWidth bound the width of the result list.
+	minListWidth = 16
+	maxListWidth = 36
+	// paneSeparator divides the result list from the details.
+	paneSeparator = " │ "
+	// crumbSeparator divides the queries in the breadcrumb trail.
+	crumbSeparator = " › "
+	// helpLine lists the keys in the status line.
+	helpLine = "↑↓ move  / search  m means  s syn  a ant  r rhyme  ⌫ back  q quit"
+
+	// ANSI escape sequences for highlighted text.
+	reverse = "\x1b[7m"
+	bold    = "\x1b[1m"
+	reset   = "\x1b[0m"
+)
+
+// Query is a query made in the browser: a mode, which is "ml", "sl",
+// "sp" or a relation code such as "syn", and the term.
+type Query struct {
+	Mode string
+	Term string
+}
+
+// String returns the query as shown in the breadcrumb trail, e.g.
+// "syn joy".
+func (q Query) String() string {
+	return q.Mode + " " + q.Term
+}
+
+// Params returns base with the constraint of the query set.
+func (q Query) Params(base datamuseapi.QueryParams) datamuseapi.QueryParams {
+	switch q.Mode {
+	case "ml":
+		base.Ml = q.Term
+	case "sl":
+		base.Sl = q.Term
+	case "sp":
+		base.Sp = q.Term
+	default:
+		base.Rel = []datamuseapi.RelatedWord{{Code: datamuseapi.RelationCode(q.Mode), Term: q.Term}}
+	}
+
+	return base
+}
+
+// pivots maps the keys that query the highlighted word to query modes.
+//
+//nolint:gochecknoglobals
+var pivots = map[Key]string{
+	KeyEnter: "ml",
+	"m":      "ml",
+	"s":      string(datamuseapi.RelSynonym),
+	"a":      string(datamuseapi.RelAntonym),
+	"r":      string(datamuseapi.RelRhyme),
+}
+
+// ActionKind tells the driver what to do after a key press.
+type ActionKind int
+
+// Actions returned by Model.HandleKey.
+const (
+	ActionNone  ActionKind = iota // Redraw.
+	ActionQuery                   // Run Action.Query and Push its results.
+	ActionQuit                    // Leave the browser.
+)
+
+// Action is the outcome of a key press.
+type Action struct {
+	Kind  ActionKind
+	Query Query
+}
+
+// frame is one query in the breadcrumb trail with its results and the
+// position in them.
+type frame struct {
+	query   Query
+	results []datamuseapi.APIResponse
+	search  string
+	visible []int // Indexes of the results matching search.
+	cursor  int   // Index into visible.
+	offset  int   // First visible entry shown.
+}
+
+// filter recomputes the results matching the search text.
+func (f *frame) filter() {
+	f.visible = f.visible[:0]
+	search := strings.ToLower(f.search)
+
+	for i, result := range f.results {
+		if strings.Contains(strings.ToLower(result.Word), search) {
+			f.visible = append(f.visible, i)
+		}
+	}
+
+	f.cursor, f.offset = 0, 0
+}
+
+// Model is the state of the browser.
+type Model struct {
+	frames    []*frame
+	searching bool
+	status    string
+	width     int
+	height    int
+}
+
+// NewModel returns an empty Model for a screen of the given size.
+func NewModel(width, height int) *Model {
+	return &Model{width: width, height: height}
+}
+
+// SetSize changes the screen size.
+func (m *Model) SetSize(width, height int) {
+	m.width, m.height = width, height
+	m.scroll()
+}
+
+// SetStatus shows a message, such as an error, in the status line until
+// the next key press.
+func (m *Model) SetStatus(status string) {
+	m.status = status
+}
+
+// Push adds a query and its results to the end of the breadcrumb trail
+// and shows them, clearing the status line.
+func (m *Model) Push(query Query, results []datamuseapi.APIResponse) {
+	f := &frame{query: query, results: results}
+	f.filter()
+
+	m.frames = append(m.frames, f)
+	m.searching = false
+	m.status = ""
+}
+
+// Breadcrumb returns the queries from the first to the current one.
+func (m *Model) Breadcrumb() []Query {
+	queries := make([]Query, len(m.frames))
+	for i, f := range m.frames {
+		queries[i] = f.query
+	}
+
+	return queries
+}
+
+// current returns the frame being shown, or nil.
+func (m *Model) current() *frame {
+	if len(m.frames) == 0 {
+		return nil
+	}
+
+	return m.frames[len(m.frames)-1]
+}
+
+// Selected returns the highlighted result.
+func (m *Model) Selected() (datamuseapi.APIResponse, bool) {
+	f := m.current()
+	if f == nil || len(f.visible) == 0 {
+		return datamuseapi.APIResponse{}, false
+	}
+
+	return f.results[f.visible[f.cursor]], true
+}
+
+// HandleKey updates the model for a key press and tells the driver what
+// to do next.
+func (m *Model) HandleKey(key Key) Action {
+	m.status = ""
+
+	if key == KeyCtrlC {
+		return Action{Kind: ActionQuit}
+	}
+
+	if m.searching {
+		m.handleSearchKey(key)
+
+		return Action{}
+	}
+
+	if mode, ok := pivots[key]; ok {
+		if selected, ok := m.Selected(); ok {
+			return Action{Kind: ActionQuery, Query: Query{Mode: mode, Term: selected.Word}}
+		}
+
+		return Action{}
+	}
+
+	switch key {
+	case "q":
+		return Action{Kind: ActionQuit}
+	case "/":
+		if f := m.current(); f != nil {
+			m.searching = true
+		}
+	case KeyEscape:
+		if f := m.current(); f != nil && f.search != "" {
+			f.search = ""
+			f.filter()
+		}
+	case KeyBackspace, KeyLeft, "b":
+		if len(m.frames) > 1 {
+			m.frames = m.frames[:len(m.frames)-1]
+		}
+	default:
+		m.move(key)
+	}
+
+	return Action{}
+}
+
+// handleSearchKey edits the search text, narrowing the list as it is
+// typed. Enter keeps the search and Escape clears it.
+func (m *Model) handleSearchKey(key Key) {
+	f := m.current()
+
+	switch key {
+	case KeyEnter:
+		m.searching = false
+	case KeyEscape:
+		m.searching = false
+		f.search = ""
+	case KeyBackspace:
+		if _, size := utf8.DecodeLastRuneInString(f.search); size > 0 {
+			f.search = f.search[:len(f.search)-size]
+		}
+	default:
+		if utf8.RuneCountInString(string(key)) != 1 {
+			return
+		}
+
+		f.search += string(key)
+	}
+
+	f.filter()
+}
+
+// move moves the highlight for a navigation key.
+func (m *Model) move(key Key) {
+	f := m.current()
+	if f == nil || len(f.visible) == 0 {
+		return
+	}
+
+	page := max(1, m.listHeight()-1)
+
+	switch key {
+	case KeyUp, "k":
+		f.cursor--
+	case KeyDown, "j":
+		f.cursor++
+	case KeyPageUp:
+		f.cursor -= page
+	case KeyPageDown, " ":
+		f.cursor += page
+	case KeyHome, "g":
+		f.cursor = 0
+	case KeyEnd, "G":
+		f.cursor = len(f.visible) - 1
+	}
+
+	f.cursor = max(0, min(f.cursor, len(f.visible)-1))
+	m.scroll()
+}
+
+// scroll keeps the highlighted result within the visible part of the
+// list.
+func (m *Model) scroll() {
+	f := m.current()
+	if f == nil {
+		return
+	}
+
+	height := max(1, m.listHeight())
+
+	if f.cursor < f.offset {
+		f.offset = f.cursor
+	} else if f.cursor >= f.offset+height {
+		f.offset = f.cursor - height + 1
+	}
+}
+
+// listHeight returns the number of rows between the breadcrumb and the
+// status line.
+func (m *Model) listHeight() int {
+	return m.height - 2 //nolint:mnd
+}
+
+// listWidth returns the width of the result list.
+func (m *Model) listWidth() int {
+	return max(minListWidth, min(maxListWidth, m.width/3)) //nolint:mnd
+}
+
+// View renders the screen: the breadcrumb trail, the result list beside
+// the details of the highlighted result, and the status line. Lines are
+// separated by "\r\n" as a terminal in raw mode needs.
+func (m *Model) View() string {
+	if m.width < 1 || m.height < 3 { //nolint:mnd
+		return ""
+	}
+
+	lines := make([]string, 0, m.height)
+
+	crumbs := []string{"Polyhymnia"}
+	for _, query := range m.Breadcrumb() {
+		crumbs = append(crumbs, query.String())
+	}
+
+	lines = append(lines, bold+fit(strings.Join(crumbs, crumbSeparator), m.width)+reset)
+
+	listWidth := m.listWidth()
+	detailWidth := max(0, m.width-listWidth-utf8.RuneCountInString(paneSeparator))
+	list := m.listLines(listWidth)
+	details := m.detailLines(detailWidth)
+
+	for row := range m.listHeight() {
+		left := strings.Repeat(" ", listWidth)
+		if row < len(list) {
+			left = list[row]
+		}
+
+		right := ""
+		if row < len(details) {
+			right = details[row]
+		}
+
+		lines = append(lines, left+paneSeparator+right)
+	}
+
+	lines = append(lines, m.statusLine())
+
+	return strings.Join(lines, "\r\n")
+}
+
+// listLines renders the visible part of the result list, each line
+// padded to width and the highlighted one in reverse video.
+func (m *Model) listLines(width int) []string {
+	f := m.current()
+	if f == nil {
+		return nil
+	}
+
+	if len(f.visible) == 0 {
+		if f.search != "" {
+			return []string{fit("(no matches)", width)}
+		}
+
+		return []string{fit("(no results)", width)}
+	}
+
+	end := min(len(f.visible), f.offset+m.listHeight())
+	lines := make([]string, 0, end-f.offset)
+
+	for i := f.offset; i < end; i++ {
+		line := fit(f.results[f.visible[i]].Word, width)
+		if i == f.cursor {
+			line = reverse + line + reset
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// partsOfSpeech are the tags that name a part of speech.
+//
+//nolint:gochecknoglobals
+var partsOfSpeech = []string{"n", "v", "adj", "adv", "u", "prop"}
+
+// detailLines renders the details of the highlighted result, wrapped to
+// width.
+func (m *Model) detailLines(width int) []string {
+	result, ok := m.Selected()
+	if !ok || width < 1 {
+		return nil
+	}
+
+	lines := []string{bold + fit(result.Word, width) + reset, ""}
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, wrap(label+": "+value, width)...)
+		}
+	}
+
+	if pron := strings.TrimSpace(result.Pronunciation); pron != "" {
+		if phonetics.IsARPAbet(pron) {
+			pron = "/" + phonetics.ToIPA(pron) + "/ (" + phonetics.ToRespelling(pron) + ")"
+		}
+
+		add("Pronunciation", pron)
+	}
+
+	if result.NumSyllables > 0 {
+		add("Syllables", strconv.Itoa(result.NumSyllables))
+	}
+
+	if result.Frequency > 0 {
+		add("Frequency", fmt.Sprintf("%.2f per million words", result.Frequency))
+	}
+
+	var pos []string
+
+	for _, tag := range result.Tags {
+		if slices.Contains(partsOfSpeech, tag) {
+			pos = append(pos, tag)
+		}
+	}
+
+	add("Part of speech", strings.Join(pos, ", "))
+	add("Score", strconv.Itoa(result.Score))
+
+	if len(result.Definitions) > 0 {
+		lines = append(lines, "", "Definitions:")
+
+		for i, definition := range result.Definitions {
+			if partOfSpeech, text, found := strings.Cut(definition, "\t"); found {
+				definition = "(" + partOfSpeech + ") " + text
+			}
+
+			lines = append(lines, wrap(fmt.Sprintf("%d. %s", i+1, definition), width)...)
+		}
+	}
+
+	return lines
+}
+
+// statusLine renders the search prompt, the status message or the key
+// help.
+func (m *Model) statusLine() string {
+	f := m.current()
+
+	switch {
+	case m.searching:
+		return fit("/"+f.search+"█", m.width)
+	case m.status != "":
+		return fit(m.status, m.width)
+	case f != nil && f.search != "":
+		return fit(fmt.Sprintf("/%s (%d of %d)  esc clear  %s", f.search, len(f.visible), len(f.results), helpLine),
+			m.width)
+	}
+
+	return fit(helpLine, m.width)
+}
+
+// fit pads or truncates s to exactly width characters.
+func fit(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	if width < 1 {
+		return ""
+	}
+
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+// wrap breaks s into lines of at most width characters at spaces,
+// cutting words longer than a line.
+func wrap(s string, width int) []string {
+	var (
+		lines []string
+		line  []rune
+	)
+
+	for _, word := range strings.Fields(s) {
+		runes := []rune(word)
+
+		if len(line) > 0 && len(line)+1+len(runes) > width {
+			lines = append(lines, string(line))
+			line = nil
+		}
+
+		if len(line) > 0 {
+			line = append(line, ' ')
+		}
+
+		line = append(line, runes...)
+
+		for len(line) > width {
+			lines = append(lines, string(line[:width]))
+			line = line[width:]
+		}
+	}
+
+	if len(line) > 0 {
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
@@ -0,0 +1,122 @@
+package tui_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/tui"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestModel returns a model showing three results for "ml ocean".
+func newTestModel() *tui.Model {
+	model := tui.NewModel(80, 10)
+	model.Push(tui.Query{Mode: "ml", Term: "ocean"}, []datamuseapi.APIResponse{
+		{Word: "sea", Score: 1001, Tags: []string{"syn", "n"}, Pronunciation: "S IY1 "},
+		{Word: "briny", Score: 998, NumSyllables: 2, Definitions: []string{"adj\tSalty, like the sea."}},
+		{Word: "deep", Score: 950},
+	})
+
+	return model
+}
+
+func TestModel_Navigation(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel()
+
+	selected, ok := model.Selected()
+	require.True(t, ok)
+	require.Equal(t, "sea", selected.Word)
+
+	model.HandleKey(tui.KeyDown)
+	model.HandleKey("j")
+	model.HandleKey(tui.KeyDown) // Stops at the last result.
+
+	selected, _ = model.Selected()
+	require.Equal(t, "deep", selected.Word)
+
+	model.HandleKey(tui.KeyHome)
+
+	selected, _ = model.Selected()
+	require.Equal(t, "sea", selected.Word)
+	require.Equal(t, tui.ActionQuit, model.HandleKey("q").Kind)
+}
+
+func TestModel_Search(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel()
+
+	for _, key := range []tui.Key{"/", "E", "e", tui.KeyEnter} {
+		require.Equal(t, tui.ActionNone, model.HandleKey(key).Kind)
+	}
+
+	selected, _ := model.Selected()
+	require.Equal(t, "deep", selected.Word)
+	require.Contains(t, model.View(), "/Ee (1 of 3)")
+
+	model.HandleKey(tui.KeyEscape)
+
+	selected, _ = model.Selected()
+	require.Equal(t, "sea", selected.Word)
+}
+
+func TestModel_PivotAndBack(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel()
+	model.HandleKey(tui.KeyDown)
+
+	action := model.HandleKey("s")
+	require.Equal(t, tui.ActionQuery, action.Kind)
+	require.Equal(t, tui.Query{Mode: "syn", Term: "briny"}, action.Query)
+
+	model.Push(action.Query, []datamuseapi.APIResponse{{Word: "salty"}})
+	require.Equal(t, []tui.Query{{Mode: "ml", Term: "ocean"}, action.Query}, model.Breadcrumb())
+	require.Contains(t, model.View(), "Polyhymnia › ml ocean › syn briny")
+
+	// Going back restores the earlier results and highlight.
+	model.HandleKey(tui.KeyBackspace)
+	require.Len(t, model.Breadcrumb(), 1)
+
+	selected, _ := model.Selected()
+	require.Equal(t, "briny", selected.Word)
+
+	// The first query stays.
+	model.HandleKey(tui.KeyBackspace)
+	require.Len(t, model.Breadcrumb(), 1)
+}
+
+func TestModel_View(t *testing.T) {
+	t.Parallel()
+
+	model := newTestModel()
+	model.HandleKey(tui.KeyDown)
+
+	lines := strings.Split(model.View(), "\r\n")
+	require.Len(t, lines, 10)
+	require.Contains(t, lines[1], "sea")
+	require.Contains(t, lines[2], "\x1b[7mbriny")
+	require.Contains(t, model.View(), "Syllables: 2")
+	require.Contains(t, model.View(), "1. (adj) Salty, like the sea.")
+
+	model.HandleKey(tui.KeyUp)
+	require.Contains(t, model.View(), "Pronunciation: /si/ (SEE)")
+	require.Contains(t, model.View(), "Part of speech: n")
+}
+
+func TestQuery_Params(t *testing.T) {
+	t.Parallel()
+
+	base := datamuseapi.QueryParams{Max: 50, Md: "dfprs"}
+
+	params := tui.Query{Mode: "rhy", Term: "day"}.Params(base)
+	require.Equal(t, []datamuseapi.RelatedWord{{Code: datamuseapi.RelRhyme, Term: "day"}}, params.Rel)
+	require.Equal(t, 50, params.Max)
+
+	params = tui.Query{Mode: "ml", Term: "ocean"}.Params(base)
+	require.Equal(t, "ocean", params.Ml)
+	require.Empty(t, params.Rel)
+}
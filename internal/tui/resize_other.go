@@ -0,0 +1,9 @@
+//go:build !unix
+
+package tui
+
+import "os"
+
+// notifyResize does nothing on systems without SIGWINCH; the screen is
+// then resized on the next key press.
+func notifyResize(chan<- os.Signal) {}
@@ -0,0 +1,208 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal reports that the browser was started without a
+// terminal to run on.
+var ErrNotTerminal = errors.New("the browser needs a terminal")
+
+const (
+	// Escape sequences that switch to the alternate screen with a hidden
+	// cursor and back.
+	enterScreen = "\x1b[?1049h\x1b[?25l"
+	leaveScreen = "\x1b[?25h\x1b[?1049l"
+	// clearScreen moves the cursor home and clears the screen.
+	clearScreen = "\x1b[H\x1b[2J"
+	// readBufferSize is the size of the buffer for key presses.
+	readBufferSize = 256
+)
+
+// Querier runs Datamuse queries. *datamuseapi.Client satisfies it.
+type Querier interface {
+	QueryContext(ctx context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error)
+}
+
+// Run shows the browser on the terminal connected to in and out, starting
+// with the results of query, until the user quits or ctx is canceled.
+// Each query is made with base and the query's constraint.
+func Run(ctx context.Context, in, out *os.File, querier Querier, base datamuseapi.QueryParams, query Query) error {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(out.Fd())) {
+		return ErrNotTerminal
+	}
+
+	results, err := querier.QueryContext(ctx, query.Params(base))
+	if err != nil {
+		return fmt.Errorf("error querying Datamuse API: %w", err)
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer term.Restore(fd, state) //nolint:errcheck
+
+	fmt.Fprint(out, enterScreen)
+	defer fmt.Fprint(out, leaveScreen)
+
+	model := NewModel(0, 0)
+	model.Push(query, results)
+
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+
+	defer signal.Stop(resized)
+
+	size := func() (int, int, error) {
+		return term.GetSize(int(out.Fd())) //nolint:wrapcheck
+	}
+
+	return loop(ctx, &screen{out: out, size: size, model: model}, readKeys(in), resized, querier, base)
+}
+
+// screen is the model drawn on a terminal of a given size.
+type screen struct {
+	out   io.Writer
+	size  func() (width, height int, err error)
+	model *Model
+}
+
+// draw resizes the model to the terminal and writes its view over the
+// whole screen.
+func (s *screen) draw() {
+	if width, height, err := s.size(); err == nil {
+		s.model.SetSize(width, height)
+	}
+
+	draw(s.out, s.model)
+}
+
+// search is a query running in the background, which reports its
+// outcome on done.
+type search struct {
+	query   Query
+	cancel  context.CancelFunc
+	done    chan struct{}
+	results []datamuseapi.APIResponse
+	err     error
+}
+
+// startSearch runs query in the background with a context that is
+// canceled with the returned search.
+func startSearch(ctx context.Context, querier Querier, base datamuseapi.QueryParams, query Query) *search {
+	ctx, cancel := context.WithCancel(ctx)
+	running := &search{query: query, cancel: cancel, done: make(chan struct{})}
+
+	go func() {
+		defer close(running.done)
+
+		running.results, running.err = querier.QueryContext(ctx, query.Params(base))
+	}()
+
+	return running
+}
+
+// loop handles key presses and query outcomes until the user quits or
+// ctx is canceled, redrawing the screen after each and when the
+// terminal is resized. Queries run in the background, so that the
+// browser stays responsive and Escape or Ctrl-C cancels a slow query.
+func loop(ctx context.Context, scr *screen, keys <-chan Key, resized <-chan os.Signal,
+	querier Querier, base datamuseapi.QueryParams,
+) error {
+	var running *search
+
+	// The query's done channel, or nil when no query is running.
+	var done <-chan struct{}
+
+	stop := func() {
+		if running != nil {
+			running.cancel()
+			running, done = nil, nil
+		}
+	}
+	defer stop()
+
+	for {
+		scr.draw()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-resized:
+		case <-done:
+			if running.err != nil {
+				scr.model.SetStatus("Error: " + running.err.Error())
+			} else {
+				scr.model.Push(running.query, running.results)
+			}
+
+			stop()
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+
+			if running != nil && (key == KeyEscape || key == KeyCtrlC) {
+				stop()
+				scr.model.SetStatus("Search canceled.")
+
+				continue
+			}
+
+			switch action := scr.model.HandleKey(key); action.Kind {
+			case ActionQuit:
+				return nil
+			case ActionQuery:
+				stop()
+
+				running = startSearch(ctx, querier, base, action.Query)
+				done = running.done
+
+				scr.model.SetStatus("Searching for " + action.Query.String() + "… (esc to cancel)")
+			case ActionNone:
+				if running != nil {
+					scr.model.SetStatus("Searching for " + running.query.String() + "… (esc to cancel)")
+				}
+			}
+		}
+	}
+}
+
+// draw writes the model's view over the whole screen.
+func draw(out io.Writer, model *Model) {
+	fmt.Fprint(out, clearScreen+model.View())
+}
+
+// readKeys sends the keys pressed on in until it can no longer be read.
+func readKeys(in io.Reader) <-chan Key {
+	keys := make(chan Key)
+
+	go func() {
+		defer close(keys)
+
+		buf := make([]byte, readBufferSize)
+
+		for {
+			n, err := in.Read(buf)
+			for _, key := range DecodeKeys(buf[:n]) {
+				keys <- key
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return keys
+}
@@ -0,0 +1,160 @@
+package tui_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pierow2k/polyhymnia/internal/datamuseapi"
+	"github.com/pierow2k/polyhymnia/internal/tui"
+	"github.com/stretchr/testify/require"
+)
+
+// frameWriter passes each screen drawn by the browser to a channel.
+type frameWriter chan string
+
+func (w frameWriter) Write(b []byte) (int, error) {
+	w <- string(b)
+
+	return len(b), nil
+}
+
+// waitFrame returns the first frame that satisfies match.
+func waitFrame(t *testing.T, frames frameWriter, match func(string) bool) string {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case frame := <-frames:
+			if match(frame) {
+				return frame
+			}
+		case <-timeout:
+			require.FailNow(t, "no matching frame was drawn")
+		}
+	}
+}
+
+// querierFunc adapts a function to the tui.Querier interface.
+type querierFunc func(ctx context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error)
+
+func (f querierFunc) QueryContext(ctx context.Context, params datamuseapi.QueryParams,
+) ([]datamuseapi.APIResponse, error) {
+	return f(ctx, params)
+}
+
+// browser runs the browser loop on newTestModel in the background. It
+// returns the channels that feed it keys and resize signals, the frames
+// it draws and a channel that receives its error once it returns.
+func browser(t *testing.T, querier tui.Querier, width *atomic.Int32,
+) (chan tui.Key, chan os.Signal, frameWriter, chan error) {
+	t.Helper()
+
+	keys := make(chan tui.Key)
+	resized := make(chan os.Signal)
+	frames := make(frameWriter, 100)
+	result := make(chan error, 1)
+
+	size := func() (int, int, error) { return int(width.Load()), 10, nil }
+
+	go func() {
+		result <- tui.RunLoop(context.Background(), frames, size, newTestModel(), keys, resized, querier,
+			datamuseapi.QueryParams{})
+	}()
+
+	return keys, resized, frames, result
+}
+
+// TestRun_QueryInBackground tests that a pivot query runs in the
+// background and that its results are shown once it completes.
+func TestRun_QueryInBackground(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	querier := querierFunc(func(_ context.Context, params datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error) {
+		<-release
+
+		return []datamuseapi.APIResponse{{Word: "ocean"}, {Word: params.Rel[0].Term + "-synonym"}}, nil
+	})
+
+	var width atomic.Int32
+	width.Store(80)
+
+	keys, _, frames, result := browser(t, querier, &width)
+
+	keys <- "s"
+	waitFrame(t, frames, func(frame string) bool { return strings.Contains(frame, "Searching for syn sea") })
+
+	// The browser still responds to keys while the query runs.
+	keys <- tui.KeyDown
+	waitFrame(t, frames, func(frame string) bool { return strings.Contains(frame, "Searching for syn sea") })
+
+	close(release)
+	waitFrame(t, frames, func(frame string) bool { return strings.Contains(frame, "sea-synonym") })
+
+	close(keys)
+	require.NoError(t, <-result)
+}
+
+// TestRun_CancelQuery tests that Escape cancels a query in progress.
+func TestRun_CancelQuery(t *testing.T) {
+	t.Parallel()
+
+	canceled := make(chan struct{})
+	querier := querierFunc(func(ctx context.Context, _ datamuseapi.QueryParams) ([]datamuseapi.APIResponse, error) {
+		<-ctx.Done()
+		close(canceled)
+
+		return nil, ctx.Err()
+	})
+
+	var width atomic.Int32
+	width.Store(80)
+
+	keys, _, frames, result := browser(t, querier, &width)
+
+	keys <- tui.KeyEnter
+	waitFrame(t, frames, func(frame string) bool { return strings.Contains(frame, "Searching for ml sea") })
+
+	keys <- tui.KeyEscape
+	waitFrame(t, frames, func(frame string) bool { return strings.Contains(frame, "Search canceled.") })
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the query was not canceled")
+	}
+
+	// Ctrl-C quits once no query is running.
+	keys <- tui.KeyCtrlC
+	require.NoError(t, <-result)
+}
+
+// TestRun_Resize tests that the screen is redrawn at the new size when
+// the terminal is resized.
+func TestRun_Resize(t *testing.T) {
+	t.Parallel()
+
+	var width atomic.Int32
+	width.Store(80)
+
+	keys, resized, frames, result := browser(t, querierFunc(nil), &width)
+	waitFrame(t, frames, func(string) bool { return true })
+
+	width.Store(40)
+	resized <- os.Interrupt
+
+	waitFrame(t, frames, func(frame string) bool {
+		status := frame[strings.LastIndex(frame, "\r\n")+2:]
+
+		return len([]rune(status)) == 40
+	})
+
+	close(keys)
+	require.NoError(t, <-result)
+}
@@ -2,7 +2,7 @@
 package main
 
 import (
-	"log"
+	"os"
 
 	"github.com/pierow2k/polyhymnia/cmd"
 )
@@ -14,9 +14,8 @@ func runApp() error {
 	return cmd.Execute()
 }
 
-// main calls runApp and handle any errors.
+// main calls runApp and exits with a status code describing any error.
+// Errors have already been printed by cmd.Execute.
 func main() {
-	if err := runApp(); err != nil {
-		log.Fatalf("Error executing command: %v", err)
-	}
+	os.Exit(cmd.ExitCode(runApp()))
 }